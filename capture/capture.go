@@ -0,0 +1,162 @@
+package capture
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/smoke7385/smk-uniden-bluetooth/transport"
+	"github.com/smoke7385/smk-uniden-bluetooth/types"
+)
+
+// Op is the kind of traffic a record holds.
+type Op string
+
+const (
+	// A notification as it arrived, which may be a fragment of a frame
+	Notify Op = "notify"
+	Write  Op = "write"
+	Read   Op = "read"
+
+	// A frame reassembled from one or more notifications
+	Frame Op = "frame"
+)
+
+// Record is one line of a capture file.
+type Record struct {
+	// Time since the capture started, taken from the monotonic clock
+	Offset time.Duration  `json:"offset"`
+	Op     Op             `json:"op"`
+	UUID   types.CharType `json:"uuid"`
+	Hex    string         `json:"hex"`
+	ASCII  string         `json:"ascii"`
+}
+
+// Data decodes the payload of the record.
+func (r Record) Data() ([]byte, error) {
+	return hex.DecodeString(r.Hex)
+}
+
+func newRecord(start time.Time, op Op, uuid types.CharType, data []byte) Record {
+	ascii := make([]byte, len(data))
+	for i, b := range data {
+		if b >= 0x20 && b < 0x7f {
+			ascii[i] = b
+		} else {
+			ascii[i] = '.'
+		}
+	}
+
+	return Record{
+		Offset: time.Since(start),
+		Op:     op,
+		UUID:   uuid,
+		Hex:    hex.EncodeToString(data),
+		ASCII:  string(ascii),
+	}
+}
+
+// Transport wraps another transport and writes every notification, write and
+// read to w as JSON lines.
+type Transport struct {
+	transport.Transport
+
+	mu    sync.Mutex
+	w     io.Writer
+	start time.Time
+}
+
+func NewTransport(t transport.Transport, w io.Writer) *Transport {
+	return &Transport{Transport: t, w: w, start: time.Now()}
+}
+
+func (c *Transport) record(op Op, uuid types.CharType, data []byte) {
+	// Keep '&' readable in the ASCII column
+	var line bytes.Buffer
+	encoder := json.NewEncoder(&line)
+	encoder.SetEscapeHTML(false)
+
+	if err := encoder.Encode(newRecord(c.start, op, uuid, data)); err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.w.Write(line.Bytes())
+}
+
+func (c *Transport) Subscribe(uuid types.CharType, callback func(buf []byte)) error {
+	return c.Transport.Subscribe(uuid, func(buf []byte) {
+		c.record(Notify, uuid, buf)
+		callback(buf)
+	})
+}
+
+// RecordFrame logs a frame reassembled from notifications. Uniden calls it for
+// every frame it decodes.
+func (c *Transport) RecordFrame(uuid types.CharType, frame []byte) {
+	c.record(Frame, uuid, frame)
+}
+
+func (c *Transport) Write(uuid types.CharType, data []byte) error {
+	c.record(Write, uuid, data)
+	return c.Transport.Write(uuid, data)
+}
+
+func (c *Transport) Read(uuid types.CharType) ([]byte, error) {
+	data, err := c.Transport.Read(uuid)
+	if err == nil {
+		c.record(Read, uuid, data)
+	}
+
+	return data, err
+}
+
+// ReadAll parses a capture file.
+func ReadAll(r io.Reader) ([]Record, error) {
+	var records []Record
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, err
+		}
+
+		records = append(records, record)
+	}
+
+	return records, scanner.Err()
+}
+
+// LastSettingsImage returns the most recent settings image in a capture,
+// whether it was read or notified. Notified images are taken from frame
+// records, notify records may only hold a fragment.
+func LastSettingsImage(records []Record) ([]byte, bool) {
+	for i := len(records) - 1; i >= 0; i-- {
+		record := records[i]
+		if record.UUID != types.C.Settings || (record.Op != Read && record.Op != Frame) {
+			continue
+		}
+
+		data, err := record.Data()
+		if err != nil || len(data) == 0 {
+			continue
+		}
+
+		return data, true
+	}
+
+	return nil, false
+}
@@ -0,0 +1,422 @@
+// Code generated by unidenctl generate. DO NOT EDIT.
+// Settings definitions revision 5
+
+export type R4SettingKey =
+  | "audio.alert_priority"
+  | "audio.auto_mute"
+  | "audio.auto_mute_volume"
+  | "audio.gatso_tone"
+  | "audio.k_bogey_tone"
+  | "audio.k_tone"
+  | "audio.ka_bogey_tone"
+  | "audio.ka_frequency_voice"
+  | "audio.ka_tone"
+  | "audio.laser_tone"
+  | "audio.mrcd_tone"
+  | "audio.mute_memory_bands"
+  | "audio.mute_memory_bands_auto"
+  | "audio.quiet_ride_beep_volume"
+  | "audio.quiet_ride_mrcd"
+  | "audio.quiet_ride_speed"
+  | "audio.voice"
+  | "audio.volume"
+  | "audio.x_tone"
+  | "bands.k"
+  | "bands.k_pop"
+  | "bands.ka"
+  | "bands.ka_pop"
+  | "bands.laser"
+  | "bands.laser_gun_id"
+  | "bands.mrcd"
+  | "bands.x"
+  | "display.alert_mode"
+  | "display.all_threats"
+  | "display.auto_dim_mode"
+  | "display.background_color"
+  | "display.backlight"
+  | "display.bright_brightness"
+  | "display.brightness"
+  | "display.dark_mode"
+  | "display.dim_brightness"
+  | "display.gatso_color"
+  | "display.k_color"
+  | "display.ka_color"
+  | "display.left"
+  | "display.mode"
+  | "display.mrcd_color"
+  | "display.scan_icon"
+  | "display.x_color"
+  | "filters.k_band"
+  | "filters.k_block_24168"
+  | "filters.k_block_24199"
+  | "filters.k_scan_width"
+  | "filters.ka_band"
+  | "filters.ka_band_alt"
+  | "filters.ka_segment_1"
+  | "filters.ka_segment_2"
+  | "filters.ka_segment_3"
+  | "filters.ka_segment_4"
+  | "filters.ka_segment_5"
+  | "filters.ka_segment_6"
+  | "filters.ka_segment_7"
+  | "filters.ka_segment_8"
+  | "filters.ka_segment_9"
+  | "filters.tsf"
+  | "general.auto_city_speed"
+  | "general.battery_saver"
+  | "general.low_battery_warning"
+  | "general.operation_mode"
+  | "general.self_test"
+  | "general.speed_units"
+  | "gps.auto_mute_memory"
+  | "gps.auto_mute_memory_enabled"
+  | "gps.enabled"
+  | "gps.limit_speed"
+  | "gps.memory_quota"
+  | "gps.poi_passchime"
+  | "gps.red_light_cameras"
+  | "gps.red_light_quiet_ride_speed"
+  | "gps.speed_camera_distance"
+  | "gps.speed_cameras"
+  | "sensitivity.k"
+  | "sensitivity.ka"
+  | "sensitivity.x"
+  | "time.bright_time"
+  | "time.dim_time"
+  | "time.dst"
+  | "time.zone";
+
+export type R8SettingKey =
+  | "audio.alert_priority"
+  | "audio.auto_mute"
+  | "audio.auto_mute_volume"
+  | "audio.gatso_tone"
+  | "audio.k_bogey_tone"
+  | "audio.k_tone"
+  | "audio.ka_bogey_tone"
+  | "audio.ka_frequency_voice"
+  | "audio.ka_tone"
+  | "audio.laser_tone"
+  | "audio.mrcd_tone"
+  | "audio.mute_memory_bands"
+  | "audio.mute_memory_bands_auto"
+  | "audio.quiet_ride_beep_volume"
+  | "audio.quiet_ride_mrcd"
+  | "audio.quiet_ride_speed"
+  | "audio.voice"
+  | "audio.volume"
+  | "audio.x_tone"
+  | "bands.k"
+  | "bands.k_pop"
+  | "bands.ka"
+  | "bands.ka_pop"
+  | "bands.laser"
+  | "bands.laser_gun_id"
+  | "bands.mrcd"
+  | "bands.x"
+  | "display.alert_mode"
+  | "display.all_threats"
+  | "display.auto_dim_mode"
+  | "display.background_color"
+  | "display.backlight"
+  | "display.bright_brightness"
+  | "display.brightness"
+  | "display.dark_mode"
+  | "display.dim_brightness"
+  | "display.gatso_color"
+  | "display.k_color"
+  | "display.ka_color"
+  | "display.left"
+  | "display.mode"
+  | "display.mrcd_color"
+  | "display.scan_icon"
+  | "display.x_color"
+  | "filters.k_band"
+  | "filters.k_block_24168"
+  | "filters.k_block_24199"
+  | "filters.k_scan_width"
+  | "filters.ka_band"
+  | "filters.ka_band_alt"
+  | "filters.ka_segment_1"
+  | "filters.ka_segment_2"
+  | "filters.ka_segment_3"
+  | "filters.ka_segment_4"
+  | "filters.ka_segment_5"
+  | "filters.ka_segment_6"
+  | "filters.ka_segment_7"
+  | "filters.ka_segment_8"
+  | "filters.ka_segment_9"
+  | "filters.tsf"
+  | "general.auto_city_speed"
+  | "general.battery_saver"
+  | "general.low_battery_warning"
+  | "general.operation_mode"
+  | "general.self_test"
+  | "general.speed_units"
+  | "gps.auto_mute_memory"
+  | "gps.auto_mute_memory_enabled"
+  | "gps.enabled"
+  | "gps.limit_speed"
+  | "gps.memory_quota"
+  | "gps.poi_passchime"
+  | "gps.red_light_cameras"
+  | "gps.red_light_quiet_ride_speed"
+  | "gps.speed_camera_distance"
+  | "gps.speed_cameras"
+  | "sensitivity.k"
+  | "sensitivity.ka"
+  | "sensitivity.x"
+  | "time.bright_time"
+  | "time.dim_time"
+  | "time.dst"
+  | "time.zone";
+
+export type R9SettingKey =
+  | "audio.alert_priority"
+  | "audio.auto_mute"
+  | "audio.auto_mute_volume"
+  | "audio.gatso_tone"
+  | "audio.k_bogey_tone"
+  | "audio.k_tone"
+  | "audio.ka_bogey_tone"
+  | "audio.ka_frequency_voice"
+  | "audio.ka_tone"
+  | "audio.laser_tone"
+  | "audio.mrcd_tone"
+  | "audio.mute_memory_bands"
+  | "audio.mute_memory_bands_auto"
+  | "audio.quiet_ride_beep_volume"
+  | "audio.quiet_ride_mrcd"
+  | "audio.quiet_ride_speed"
+  | "audio.voice"
+  | "audio.volume"
+  | "audio.x_tone"
+  | "bands.k_pop"
+  | "bands.ka_pop"
+  | "bands.laser"
+  | "bands.laser_gun_id"
+  | "bands.mrcd"
+  | "bands.x"
+  | "display.alert_mode"
+  | "display.all_threats"
+  | "display.auto_dim_mode"
+  | "display.background_color"
+  | "display.backlight"
+  | "display.bright_brightness"
+  | "display.brightness"
+  | "display.dark_mode"
+  | "display.dim_brightness"
+  | "display.left"
+  | "display.mode"
+  | "display.scan_icon"
+  | "filters.k_band"
+  | "filters.k_scan_width"
+  | "filters.ka_band"
+  | "filters.ka_band_alt"
+  | "filters.ka_segment_1"
+  | "filters.ka_segment_2"
+  | "filters.ka_segment_3"
+  | "filters.ka_segment_4"
+  | "filters.ka_segment_5"
+  | "filters.ka_segment_6"
+  | "filters.ka_segment_7"
+  | "filters.ka_segment_8"
+  | "filters.ka_segment_9"
+  | "filters.tsf"
+  | "general.auto_city_speed"
+  | "general.battery_saver"
+  | "general.low_battery_warning"
+  | "general.operation_mode"
+  | "general.self_test"
+  | "general.speed_units"
+  | "gps.auto_mute_memory"
+  | "gps.auto_mute_memory_enabled"
+  | "gps.enabled"
+  | "gps.limit_speed"
+  | "gps.memory_quota"
+  | "gps.poi_passchime"
+  | "gps.red_light_cameras"
+  | "gps.red_light_quiet_ride_speed"
+  | "gps.speed_camera_distance"
+  | "gps.speed_cameras"
+  | "time.bright_time"
+  | "time.dim_time"
+  | "time.dst"
+  | "time.zone";
+
+export type Model = "R4" | "R8" | "R9";
+
+export type SettingKey =
+  | "audio.alert_priority"
+  | "audio.auto_mute"
+  | "audio.auto_mute_volume"
+  | "audio.gatso_tone"
+  | "audio.k_bogey_tone"
+  | "audio.k_tone"
+  | "audio.ka_bogey_tone"
+  | "audio.ka_frequency_voice"
+  | "audio.ka_tone"
+  | "audio.laser_tone"
+  | "audio.mrcd_tone"
+  | "audio.mute_memory_bands"
+  | "audio.mute_memory_bands_auto"
+  | "audio.quiet_ride_beep_volume"
+  | "audio.quiet_ride_mrcd"
+  | "audio.quiet_ride_speed"
+  | "audio.voice"
+  | "audio.volume"
+  | "audio.x_tone"
+  | "bands.k"
+  | "bands.k_pop"
+  | "bands.ka"
+  | "bands.ka_pop"
+  | "bands.laser"
+  | "bands.laser_gun_id"
+  | "bands.mrcd"
+  | "bands.x"
+  | "display.alert_mode"
+  | "display.all_threats"
+  | "display.auto_dim_mode"
+  | "display.background_color"
+  | "display.backlight"
+  | "display.bright_brightness"
+  | "display.brightness"
+  | "display.dark_mode"
+  | "display.dim_brightness"
+  | "display.gatso_color"
+  | "display.k_color"
+  | "display.ka_color"
+  | "display.left"
+  | "display.mode"
+  | "display.mrcd_color"
+  | "display.scan_icon"
+  | "display.x_color"
+  | "filters.k_band"
+  | "filters.k_block_24168"
+  | "filters.k_block_24199"
+  | "filters.k_scan_width"
+  | "filters.ka_band"
+  | "filters.ka_band_alt"
+  | "filters.ka_segment_1"
+  | "filters.ka_segment_2"
+  | "filters.ka_segment_3"
+  | "filters.ka_segment_4"
+  | "filters.ka_segment_5"
+  | "filters.ka_segment_6"
+  | "filters.ka_segment_7"
+  | "filters.ka_segment_8"
+  | "filters.ka_segment_9"
+  | "filters.tsf"
+  | "general.auto_city_speed"
+  | "general.battery_saver"
+  | "general.low_battery_warning"
+  | "general.operation_mode"
+  | "general.self_test"
+  | "general.speed_units"
+  | "gps.auto_mute_memory"
+  | "gps.auto_mute_memory_enabled"
+  | "gps.enabled"
+  | "gps.limit_speed"
+  | "gps.memory_quota"
+  | "gps.poi_passchime"
+  | "gps.red_light_cameras"
+  | "gps.red_light_quiet_ride_speed"
+  | "gps.speed_camera_distance"
+  | "gps.speed_cameras"
+  | "sensitivity.k"
+  | "sensitivity.ka"
+  | "sensitivity.x"
+  | "time.bright_time"
+  | "time.dim_time"
+  | "time.dst"
+  | "time.zone";
+
+export type SettingCategory =
+  | "Audio"
+  | "Bands"
+  | "Display"
+  | "Filters"
+  | "GPS"
+  | "General"
+  | "Sensitivity"
+  | "Time";
+
+export type SettingKind =
+  | "bool"
+  | "enum"
+  | "range"
+  | "speed"
+  | "time-of-day";
+
+export type GPSState = "Connected" | "Disconnected" | "Unknown";
+
+export interface SerializedSetting {
+  key: SettingKey;
+  name: string;
+  category: SettingCategory;
+  kind: SettingKind;
+  unit?: string;
+  description?: string;
+  min?: number;
+  max?: number;
+  step?: number;
+  value: string;
+  values: string;
+}
+
+export interface SettingValue {
+  name?: string;
+  int?: number;
+}
+
+export interface RadarEvent {
+  Slot: number;
+  Priority: number;
+  Direction: string;
+  Band: string;
+  Strength: number;
+  Frequency: number;
+  Distance: number;
+  Muted: boolean;
+  Locked: boolean;
+  Raw: string[] | null;
+  LastUpdate: string;
+}
+
+export interface GPS {
+  Heading: string;
+  Speed: number;
+  Altitude: number;
+  State: GPSState;
+  Raw: string[] | null;
+}
+
+export interface Status {
+  Voltage: number;
+  Signal: number;
+  GPS: GPS;
+  Raw: string[] | null;
+}
+
+export interface SimulatedCommand {
+  command: string;
+  verb?: string;
+  setting?: string;
+  value: number;
+  time: string;
+}
+
+// Parsed payloads of the server events
+export type SettingsUpdatePayload = SerializedSetting[];
+export type RadarEventPayload = RadarEvent[];
+export type StatusUpdatePayload = Status;
+export type SimulatedCommandPayload = SimulatedCommand;
+
+// Events emitted by the server. Payloads are JSON strings.
+export interface ServerEvents {
+  settingsUpdate: (settings: string, address: string) => void;
+  radarEvent: (alerts: string, address: string) => void;
+  statusUpdate: (status: string, address: string) => void;
+  simulatedCommand: (command: string, address: string) => void;
+}
+
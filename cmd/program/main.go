@@ -1,15 +1,32 @@
 package main
 
 import (
-	"strconv"
+	"context"
+	"os"
+	"os/signal"
+
 	"time"
 
+	"github.com/smoke7385/smk-uniden-bluetooth/command"
 	"github.com/smoke7385/smk-uniden-bluetooth/uniden"
 	"github.com/smoke7385/smk-uniden-bluetooth/utils"
 )
 
 func main() {
-	unidenInstance := uniden.NewUniden("R4")
+	// Settings definitions patch, used instead of the embedded ones
+	if path := os.Getenv("UNIDEN_DEFINITIONS"); path != "" {
+		err := uniden.LoadDefinitions(path)
+		if err != nil {
+			println("Failed to load settings definitions:", err.Error())
+			return
+		}
+	}
+
+	unidenInstance, err := uniden.NewUniden("R4")
+	if err != nil {
+		println("Failed to create uniden:", err.Error())
+		return
+	}
 
 	unidenInstance.OnStatusUpdate(func(status uniden.Status) {
 		// println("Status updated:")
@@ -27,14 +44,19 @@ func main() {
 		// }
 	})
 
-	err := unidenInstance.Connect("E0:00:00:00:4F:C5")
+	// Connect to the given address, or to the nearest detector if none is given
+	if len(os.Args) > 1 {
+		err = unidenInstance.Connect(os.Args[1])
+	} else {
+		_, err = unidenInstance.ConnectFirst(context.Background(), 10*time.Second)
+	}
 
 	if err != nil {
 		println("Failed to connect to device:", err)
 		return
 	}
 
-	// unidenInstance.SendArbitraryCommand("BTreqSETC:50=2")
+	// unidenInstance.SendCommand(context.Background(), command.SetSetting(50, 2))
 
 	// go func() {
 	// 	time.Sleep(1 * time.Second)
@@ -42,9 +64,22 @@ func main() {
 	// }()
 
 	_, err = unidenInstance.StartServer(8080)
+	if err != nil {
+		println("Failed to start server:", err.Error())
+	}
+
+	// Run until interrupted, then shut down cleanly
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	done := test(unidenInstance)
+	<-ctx.Done()
+	close(done)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
 
-	go test(unidenInstance)
-	unidenInstance.StayOpen()
+	unidenInstance.Close(shutdownCtx)
 }
 
 var i int = 0
@@ -58,11 +93,14 @@ func ic() int {
 	return i
 }
 
-func test(uniden *uniden.Uniden) {
+func test(uniden *uniden.Uniden) chan bool {
 	// Call setInterval with the desired interval and callback function
-	utils.SetInterval(func() {
-		str := "BTreqSETC:50=" + strconv.Itoa(ic())
-		uniden.SendArbitraryCommand(str)
+	return utils.SetInterval(func() {
+		// Queued, so a slow device is never written faster than it confirms
+		_, err := uniden.SendCommand(context.Background(), command.SetSetting(50, ic()))
+		if err != nil {
+			println("Command failed:", err.Error())
+		}
 		// println()
 	}, 500*time.Millisecond)
 }
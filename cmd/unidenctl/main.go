@@ -0,0 +1,164 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/smoke7385/smk-uniden-bluetooth/capture"
+	"github.com/smoke7385/smk-uniden-bluetooth/schema"
+	"github.com/smoke7385/smk-uniden-bluetooth/types"
+	"github.com/smoke7385/smk-uniden-bluetooth/uniden"
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: unidenctl <command> [arguments]")
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "commands:")
+	fmt.Fprintln(os.Stderr, "  diff -model R4 before.jsonl after.jsonl   compare the settings images of two captures")
+	fmt.Fprintln(os.Stderr, "  validate [-warnings] [definitions.json]   check settings definitions, the embedded ones by default")
+	fmt.Fprintln(os.Stderr, "  generate [-out dir] [definitions.json]    write JSON Schemas and TypeScript types for clients")
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "diff":
+		err = diff(os.Args[2:])
+	case "validate":
+		err = validate(os.Args[2:])
+	case "generate":
+		err = generate(os.Args[2:])
+	default:
+		usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "unidenctl:", err)
+		os.Exit(1)
+	}
+}
+
+func diff(args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	model := fs.String("model", string(types.R4), "detector model the captures were taken from")
+	fs.Parse(args)
+
+	if fs.NArg() != 2 {
+		return fmt.Errorf("diff needs two capture files")
+	}
+
+	before, err := readSettingsImage(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	after, err := readSettingsImage(fs.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	changes := uniden.DiffSettingsImages(types.Model(*model), before, after)
+	if len(changes) == 0 {
+		fmt.Println("settings images are identical")
+		return nil
+	}
+
+	for _, change := range changes {
+		name := "unknown"
+		if len(change.Settings) > 0 {
+			name = strings.Join(change.Settings, ", ")
+		}
+
+		fmt.Printf("index %3d: %3d -> %3d  %s\n", change.Index, change.Before, change.After, name)
+	}
+
+	return nil
+}
+
+func validate(args []string) error {
+	fs := flag.NewFlagSet("validate", flag.ExitOnError)
+	warnings := fs.Bool("warnings", false, "also list warnings such as missing defaults")
+	fs.Parse(args)
+
+	if fs.NArg() > 1 {
+		return fmt.Errorf("validate takes at most one definitions file")
+	}
+
+	if fs.NArg() == 1 {
+		err := uniden.LoadDefinitions(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+	}
+
+	failed := 0
+	for _, problem := range uniden.ValidateDefinitions() {
+		if problem.Warning() {
+			if *warnings {
+				fmt.Println("warning:", problem)
+			}
+			continue
+		}
+
+		fmt.Println(problem)
+		failed++
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d problems in settings definitions revision %s", failed, uniden.DefinitionsRevision())
+	}
+
+	return nil
+}
+
+func generate(args []string) error {
+	fs := flag.NewFlagSet("generate", flag.ExitOnError)
+	out := fs.String("out", "clients", "directory to write the generated files to")
+	fs.Parse(args)
+
+	if fs.NArg() > 1 {
+		return fmt.Errorf("generate takes at most one definitions file")
+	}
+
+	if fs.NArg() == 1 {
+		err := uniden.LoadDefinitions(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+	}
+
+	written, err := schema.Generate(*out)
+	for _, path := range written {
+		fmt.Println("wrote", path)
+	}
+
+	return err
+}
+
+func readSettingsImage(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	records, err := capture.ReadAll(f)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+
+	image, ok := capture.LastSettingsImage(records)
+	if !ok {
+		return nil, fmt.Errorf("%s holds no settings image", path)
+	}
+
+	return image, nil
+}
@@ -0,0 +1,105 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/smoke7385/smk-uniden-bluetooth/protocol"
+)
+
+// Verb names what a command does, BTreq<VERB>:<payload>.
+type Verb string
+
+const (
+	// BTreqSETC:<storage index>=<value>
+	VerbSetSetting Verb = "SETC"
+)
+
+var (
+	ErrNotACommand    = errors.New("not a command")
+	ErrMissingVerb    = errors.New("missing verb")
+	ErrInvalidPayload = errors.New("invalid payload")
+)
+
+// Command is a single write to the command characteristic.
+type Command struct {
+	Verb    Verb
+	Payload string
+
+	// Decoded payload of SETC
+	Index int
+	Value int
+}
+
+// Table validates settings values, usually the settings of a Uniden.
+type Table interface {
+	ValidateStorageValue(index int, value int) error
+}
+
+// SetSetting writes value to the setting at the given storage index.
+func SetSetting(index int, value int) Command {
+	return Command{
+		Verb:    VerbSetSetting,
+		Payload: strconv.Itoa(index) + "=" + strconv.Itoa(value),
+		Index:   index,
+		Value:   value,
+	}
+}
+
+// Raw builds a command for a verb that has no constructor yet.
+func Raw(verb Verb, payload string) Command {
+	return Command{Verb: verb, Payload: payload}
+}
+
+// Validate checks a SETC command against a settings table. Other verbs carry
+// nothing to validate.
+func (c Command) Validate(table Table) error {
+	if c.Verb != VerbSetSetting {
+		return nil
+	}
+
+	return table.ValidateStorageValue(c.Index, c.Value)
+}
+
+func (c Command) String() string {
+	return protocol.RequestPrefix + string(c.Verb) + ":" + c.Payload
+}
+
+func (c Command) Encode() []byte {
+	return []byte(c.String())
+}
+
+// ParseCommand decodes a write to the command characteristic, e.g. for
+// simulators and capture tools.
+func ParseCommand(buf []byte) (Command, error) {
+	raw := string(buf)
+	if !strings.HasPrefix(raw, protocol.RequestPrefix) {
+		return Command{}, fmt.Errorf("%w: %q", ErrNotACommand, raw)
+	}
+
+	verb, payload, ok := strings.Cut(raw[len(protocol.RequestPrefix):], ":")
+	if !ok || verb == "" {
+		return Command{}, fmt.Errorf("%w: %q", ErrMissingVerb, raw)
+	}
+
+	c := Command{Verb: Verb(verb), Payload: payload}
+
+	if c.Verb == VerbSetSetting {
+		index, value, ok := strings.Cut(payload, "=")
+		if !ok {
+			return Command{}, fmt.Errorf("%w: %q", ErrInvalidPayload, raw)
+		}
+
+		var err error
+		if c.Index, err = strconv.Atoi(index); err != nil {
+			return Command{}, fmt.Errorf("%w: index %q", ErrInvalidPayload, index)
+		}
+		if c.Value, err = strconv.Atoi(value); err != nil {
+			return Command{}, fmt.Errorf("%w: value %q", ErrInvalidPayload, value)
+		}
+	}
+
+	return c, nil
+}
@@ -2,6 +2,11 @@ module github.com/smoke7385/smk-uniden-bluetooth
 
 go 1.22.3
 
+require (
+	github.com/zishang520/socket.io/v2 v2.2.0
+	tinygo.org/x/bluetooth v0.9.0
+)
+
 require (
 	github.com/andybalholm/brotli v1.1.0 // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
@@ -29,7 +34,6 @@ require (
 	github.com/zishang520/engine.io-go-parser v1.2.5 // indirect
 	github.com/zishang520/engine.io/v2 v2.1.1 // indirect
 	github.com/zishang520/socket.io-go-parser/v2 v2.1.0 // indirect
-	github.com/zishang520/socket.io/v2 v2.2.0 // indirect
 	go.uber.org/mock v0.4.0 // indirect
 	golang.org/x/crypto v0.23.0 // indirect
 	golang.org/x/exp v0.0.0-20240506185415-9bf2ced13842 // indirect
@@ -38,5 +42,4 @@ require (
 	golang.org/x/sys v0.20.0 // indirect
 	golang.org/x/text v0.15.0 // indirect
 	golang.org/x/tools v0.21.0 // indirect
-	tinygo.org/x/bluetooth v0.9.0 // indirect
 )
@@ -0,0 +1,80 @@
+package protocol
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/smoke7385/smk-uniden-bluetooth/types"
+)
+
+var ErrUnsupportedModel = errors.New("unsupported model")
+
+// Decoder turns raw frames from one detector model into typed values. Models
+// whose frames differ register their own decoder instead of branching inside
+// the shared parsers.
+type Decoder interface {
+	DecodeRadar(buf []byte) ([]RadarEvent, error)
+	DecodeStatus(buf []byte) (Status, error)
+	DecodeSettings(buf []byte) (SettingsImage, error)
+}
+
+// StandardDecoder handles the frame format shared by every known model.
+type StandardDecoder struct{}
+
+func (StandardDecoder) DecodeRadar(buf []byte) ([]RadarEvent, error) {
+	return ParseRadarFrame(buf)
+}
+
+func (StandardDecoder) DecodeStatus(buf []byte) (Status, error) {
+	return ParseStatusFrame(buf)
+}
+
+func (StandardDecoder) DecodeSettings(buf []byte) (SettingsImage, error) {
+	return ParseSettingsImage(buf)
+}
+
+type decoderKey struct {
+	model    types.Model
+	firmware string
+}
+
+var (
+	decoders   = map[decoderKey]Decoder{}
+	decodersMu sync.RWMutex
+)
+
+func init() {
+	// R9 specific fields, such as the directional arrows, belong in an R9
+	// decoder once their encoding is known.
+	for _, model := range types.Models {
+		Register(model, "", StandardDecoder{})
+	}
+}
+
+// Register sets the decoder for a model. An empty firmware registers the
+// default for the model, otherwise the decoder only applies to devices
+// reporting exactly that firmware revision.
+func Register(model types.Model, firmware string, decoder Decoder) {
+	decodersMu.Lock()
+	defer decodersMu.Unlock()
+
+	decoders[decoderKey{model, firmware}] = decoder
+}
+
+// Lookup returns the decoder for a model and firmware revision, falling back
+// to the model default.
+func Lookup(model types.Model, firmware string) (Decoder, error) {
+	decodersMu.RLock()
+	defer decodersMu.RUnlock()
+
+	if decoder, ok := decoders[decoderKey{model, firmware}]; ok && firmware != "" {
+		return decoder, nil
+	}
+
+	if decoder, ok := decoders[decoderKey{model, ""}]; ok {
+		return decoder, nil
+	}
+
+	return nil, fmt.Errorf("%w: %q", ErrUnsupportedModel, model)
+}
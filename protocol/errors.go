@@ -0,0 +1,53 @@
+package protocol
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrEmptyFrame    = errors.New("empty frame")
+	ErrMissingField  = errors.New("missing field")
+	ErrInvalidNumber = errors.New("invalid number")
+	ErrFrameTooLarge = errors.New("frame too large")
+)
+
+// ParseError describes where in a frame parsing failed. Offset is the byte
+// offset of the offending field from the start of the frame.
+type ParseError struct {
+	Frame  string
+	Field  string
+	Offset int
+	Value  string
+	Err    error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("parse %s frame: %s at offset %d (%q): %v", e.Frame, e.Field, e.Offset, e.Value, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// field is a piece of a frame together with its byte offset in the frame.
+type field struct {
+	value  string
+	offset int
+}
+
+// split splits data on sep, keeping the offset of every piece. base is the
+// offset of data itself within the frame.
+func split(data string, sep byte, base int) []field {
+	var fields []field
+
+	start := 0
+	for i := 0; i < len(data); i++ {
+		if data[i] == sep {
+			fields = append(fields, field{data[start:i], base + start})
+			start = i + 1
+		}
+	}
+
+	return append(fields, field{data[start:], base + start})
+}
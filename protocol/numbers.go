@@ -0,0 +1,32 @@
+package protocol
+
+import "strconv"
+
+func parseInt(frame string, name string, f field) (int, error) {
+	value, err := strconv.Atoi(f.value)
+	if err != nil {
+		return 0, &ParseError{Frame: frame, Field: name, Offset: f.offset, Value: f.value, Err: ErrInvalidNumber}
+	}
+
+	return value, nil
+}
+
+func parseFloat32(frame string, name string, f field) (float32, error) {
+	value, err := strconv.ParseFloat(f.value, 32)
+	if err != nil {
+		return 0, &ParseError{Frame: frame, Field: name, Offset: f.offset, Value: f.value, Err: ErrInvalidNumber}
+	}
+
+	return float32(value), nil
+}
+
+func parseBool(frame string, name string, f field) (bool, error) {
+	switch f.value {
+	case "0":
+		return false, nil
+	case "1":
+		return true, nil
+	default:
+		return false, &ParseError{Frame: frame, Field: name, Offset: f.offset, Value: f.value, Err: ErrInvalidNumber}
+	}
+}
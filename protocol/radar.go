@@ -0,0 +1,109 @@
+package protocol
+
+import (
+	"time"
+
+	"github.com/smoke7385/smk-uniden-bluetooth/types"
+)
+
+// RadarEvent is one alert slot of a radar frame, e.g. 1,00,K,5,123,24.1090,0,1
+//
+// Band, strength and frequency are known. The other fields are inferred from
+// captures and unverified; values that do not parse leave them at their zero
+// value, and Raw always holds what was received.
+type RadarEvent struct {
+	// Position of the alert in the frame
+	Slot int
+
+	// Unverified: the priority the device gave the alert
+	Priority int
+
+	// Unverified: two digit direction code, "00" on detectors without
+	// directional antennas
+	Direction string
+
+	Band      types.Band
+	Strength  int
+	Frequency float32
+
+	// Unverified: distance to a known camera, counting down as it is approached
+	Distance int
+
+	// Unverified
+	Muted  bool
+	Locked bool
+
+	// Every comma-separated field as received, for fields not decoded yet
+	Raw []string
+
+	LastUpdate time.Time
+}
+
+const radarFields = 8
+
+// ParseRadarFrame parses a radar frame: up to four "&" separated alert slots,
+// each either "0" for an empty slot or at least eight comma-separated fields.
+// Empty slots are returned as events with only Slot set, so every alert keeps
+// its position.
+func ParseRadarFrame(buf []byte) ([]RadarEvent, error) {
+	if len(buf) == 0 {
+		return nil, &ParseError{Frame: "radar", Field: "frame", Err: ErrEmptyFrame}
+	}
+
+	var events []RadarEvent
+	for index, slot := range split(string(buf), '&', 0) {
+		if slot.value == "0" {
+			events = append(events, RadarEvent{Slot: index})
+			continue
+		}
+
+		event, err := parseRadarSlot(index, slot)
+		if err != nil {
+			return nil, err
+		}
+
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+// 1,00,K,5,123,24.1090,0,1
+// priority, direction, band, strength, distance, frequency, muted, locked
+func parseRadarSlot(index int, slot field) (RadarEvent, error) {
+	fields := split(slot.value, ',', slot.offset)
+	if len(fields) < radarFields {
+		end := slot.offset + len(slot.value)
+		return RadarEvent{}, &ParseError{Frame: "radar", Field: "slot", Offset: end, Value: slot.value, Err: ErrMissingField}
+	}
+
+	event := RadarEvent{
+		Slot:      index,
+		Direction: fields[1].value,
+		Band:      types.Band(fields[2].value),
+	}
+
+	for _, f := range fields {
+		event.Raw = append(event.Raw, f.value)
+	}
+
+	if fields[2].value == "" {
+		return RadarEvent{}, &ParseError{Frame: "radar", Field: "band", Offset: fields[2].offset, Err: ErrMissingField}
+	}
+
+	var err error
+	if event.Strength, err = parseInt("radar", "strength", fields[3]); err != nil {
+		return RadarEvent{}, err
+	}
+	if event.Frequency, err = parseFloat32("radar", "frequency", fields[5]); err != nil {
+		return RadarEvent{}, err
+	}
+
+	// Unverified fields never fail the frame
+	event.Priority, _ = parseInt("radar", "priority", fields[0])
+	event.Distance, _ = parseInt("radar", "distance", fields[4])
+	event.Muted, _ = parseBool("radar", "muted", fields[6])
+	event.Locked, _ = parseBool("radar", "locked", fields[7])
+
+	return event, nil
+}
@@ -0,0 +1,142 @@
+package protocol
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/smoke7385/smk-uniden-bluetooth/types"
+)
+
+// Only the first frame is a real capture. The others are built from it to
+// cover the frame structure, they have not been seen from a device.
+func TestParseRadarFrame(t *testing.T) {
+	tests := []struct {
+		name  string
+		frame string
+		want  []RadarEvent
+	}{
+		{
+			name:  "captured K alert",
+			frame: "1,00,K,5,123,24.1090,0,1",
+			want: []RadarEvent{{
+				Slot: 0, Priority: 1, Direction: "00", Band: types.K, Strength: 5,
+				Distance: 123, Frequency: 24.109, Muted: false, Locked: true,
+				Raw: []string{"1", "00", "K", "5", "123", "24.1090", "0", "1"},
+			}},
+		},
+		{
+			name:  "empty slots keep their position",
+			frame: "0&2,00,Ka,3,0,34.7000,1,0&0&0",
+			want: []RadarEvent{
+				{Slot: 0},
+				{
+					Slot: 1, Priority: 2, Direction: "00", Band: types.Ka, Strength: 3,
+					Frequency: 34.7, Muted: true,
+					Raw: []string{"2", "00", "Ka", "3", "0", "34.7000", "1", "0"},
+				},
+				{Slot: 2},
+				{Slot: 3},
+			},
+		},
+		{
+			name:  "extra fields are kept in Raw",
+			frame: "1,02,K,5,123,24.1090,0,1,9",
+			want: []RadarEvent{{
+				Priority: 1, Direction: "02", Band: types.K, Strength: 5,
+				Distance: 123, Frequency: 24.109, Locked: true,
+				Raw: []string{"1", "02", "K", "5", "123", "24.1090", "0", "1", "9"},
+			}},
+		},
+		{
+			name:  "unverified fields do not fail the frame",
+			frame: "x,00,K,5,-,24.1090,2,?",
+			want: []RadarEvent{{
+				Direction: "00", Band: types.K, Strength: 5, Frequency: 24.109,
+				Raw: []string{"x", "00", "K", "5", "-", "24.1090", "2", "?"},
+			}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ParseRadarFrame([]byte(test.frame))
+			if err != nil {
+				t.Fatalf("ParseRadarFrame(%q): %v", test.frame, err)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("ParseRadarFrame(%q)\n got %+v\nwant %+v", test.frame, got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseRadarFrameErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		frame  string
+		field  string
+		offset int
+		err    error
+	}{
+		{"empty frame", "", "frame", 0, ErrEmptyFrame},
+		{"truncated slot", "1,00,K,5,123", "slot", 12, ErrMissingField},
+		{"missing band", "1,00,,5,123,24.1090,0,1", "band", 5, ErrMissingField},
+		{"invalid strength", "1,00,K,x,123,24.1090,0,1", "strength", 7, ErrInvalidNumber},
+		{"invalid frequency", "0&1,00,K,5,123,24.1O90,0,1", "frequency", 15, ErrInvalidNumber},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ParseRadarFrame([]byte(test.frame))
+
+			var perr *ParseError
+			if !errors.As(err, &perr) {
+				t.Fatalf("ParseRadarFrame(%q) = %v, want a ParseError", test.frame, err)
+			}
+
+			if !errors.Is(err, test.err) || perr.Field != test.field || perr.Offset != test.offset {
+				t.Errorf("ParseRadarFrame(%q) = %v, want %v on %s at offset %d", test.frame, err, test.err, test.field, test.offset)
+			}
+		})
+	}
+}
+
+func FuzzParseRadarFrame(f *testing.F) {
+	f.Add([]byte("1,00,K,5,123,24.1090,0,1"))
+	f.Add([]byte("0&2,00,Ka,3,0,34.7000,1,0&0&0"))
+	f.Add([]byte("1,02,K,5,123,24.1090,0,1,9"))
+	f.Add([]byte("1,00,,5"))
+
+	f.Fuzz(func(t *testing.T, frame []byte) {
+		events, err := ParseRadarFrame(frame)
+		if err != nil {
+			checkParseError(t, frame, err)
+			return
+		}
+
+		for index, event := range events {
+			if event.Slot != index {
+				t.Errorf("event %d has slot %d", index, event.Slot)
+			}
+			if len(event.Raw) > 0 && event.Band == "" {
+				t.Errorf("event %d decoded without a band: %+v", index, event)
+			}
+		}
+	})
+}
+
+// checkParseError fails unless err is a ParseError pointing into frame.
+func checkParseError(t *testing.T, frame []byte, err error) {
+	t.Helper()
+
+	var perr *ParseError
+	if !errors.As(err, &perr) {
+		t.Fatalf("error is not a ParseError: %v", err)
+	}
+
+	if perr.Offset < 0 || perr.Offset > len(frame) {
+		t.Errorf("offset %d outside frame of %d bytes: %v", perr.Offset, len(frame), err)
+	}
+}
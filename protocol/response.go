@@ -0,0 +1,81 @@
+package protocol
+
+import "strings"
+
+// Commands are written as BTreq<VERB>:<payload>. Replies on the response
+// characteristic are assumed to mirror that as BTrsp<VERB>:<payload>, but no
+// capture shows a reply yet.
+const (
+	RequestPrefix  = "BTreq"
+	ResponsePrefix = "BTrsp"
+)
+
+// ResponseStatus says whether the device accepted a command.
+type ResponseStatus int
+
+const (
+	ResponseOK ResponseStatus = iota
+	ResponseRejected
+	// Not in the assumed reply format, only Raw is set
+	ResponseUnparsed
+)
+
+func (s ResponseStatus) String() string {
+	switch s {
+	case ResponseOK:
+		return "OK"
+	case ResponseRejected:
+		return "Rejected"
+	case ResponseUnparsed:
+		return "Unparsed"
+	default:
+		return "Unknown"
+	}
+}
+
+func (s ResponseStatus) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// Response is a reply on the response characteristic, e.g. BTrspSETC:50=2
+type Response struct {
+	Verb    string
+	Payload string
+	Status  ResponseStatus
+	Raw     string
+}
+
+// Payloads assumed to reject a command. Unverified, no capture shows one.
+var rejections = []string{"NG", "ERR", "FAIL"}
+
+// ParseResponse parses a reply from the response characteristic. A reply
+// outside the assumed format is returned as a ResponseUnparsed response
+// holding the raw reply, together with the error.
+func ParseResponse(buf []byte) (Response, error) {
+	raw := strings.TrimRight(string(buf), "\x00\r\n")
+	unparsed := Response{Status: ResponseUnparsed, Raw: raw}
+
+	if raw == "" {
+		return unparsed, &ParseError{Frame: "response", Field: "frame", Err: ErrEmptyFrame}
+	}
+
+	if !strings.HasPrefix(raw, ResponsePrefix) {
+		return unparsed, &ParseError{Frame: "response", Field: "prefix", Value: raw, Err: ErrMissingField}
+	}
+
+	verb, payload, ok := strings.Cut(raw[len(ResponsePrefix):], ":")
+	if !ok || verb == "" {
+		return unparsed, &ParseError{Frame: "response", Field: "verb", Offset: len(ResponsePrefix), Value: raw, Err: ErrMissingField}
+	}
+
+	response := Response{Verb: verb, Payload: payload, Raw: raw}
+
+	upper := strings.ToUpper(payload)
+	for _, rejection := range rejections {
+		if strings.HasPrefix(upper, rejection) {
+			response.Status = ResponseRejected
+		}
+	}
+
+	return response, nil
+}
@@ -0,0 +1,55 @@
+package protocol
+
+import (
+	"reflect"
+	"testing"
+)
+
+// No reply has been captured yet, these follow the assumed format.
+func TestParseResponse(t *testing.T) {
+	tests := []struct {
+		name    string
+		reply   string
+		want    Response
+		wantErr bool
+	}{
+		{
+			name:  "accepted",
+			reply: "BTrspSETC:50=2\r\n",
+			want:  Response{Verb: "SETC", Payload: "50=2", Raw: "BTrspSETC:50=2"},
+		},
+		{
+			name:  "rejected",
+			reply: "BTrspSETC:NG",
+			want:  Response{Verb: "SETC", Payload: "NG", Status: ResponseRejected, Raw: "BTrspSETC:NG"},
+		},
+		{
+			name:    "other format kept raw",
+			reply:   "OK",
+			want:    Response{Status: ResponseUnparsed, Raw: "OK"},
+			wantErr: true,
+		},
+		{
+			name:    "missing verb kept raw",
+			reply:   "BTrsp:1",
+			want:    Response{Status: ResponseUnparsed, Raw: "BTrsp:1"},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ParseResponse([]byte(test.reply))
+			if (err != nil) != test.wantErr {
+				t.Fatalf("ParseResponse(%q) error %v, want error %v", test.reply, err, test.wantErr)
+			}
+			if err != nil {
+				checkParseError(t, []byte(test.reply), err)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("ParseResponse(%q)\n got %+v\nwant %+v", test.reply, got, test.want)
+			}
+		})
+	}
+}
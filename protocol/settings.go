@@ -0,0 +1,29 @@
+package protocol
+
+// Largest settings image accepted, well above every known model.
+const maxSettingsImage = 512
+
+// SettingsImage is the raw settings buffer, one byte per storage index.
+type SettingsImage []byte
+
+// Value returns the byte at a storage index.
+func (s SettingsImage) Value(index int) (int, bool) {
+	if index < 0 || index >= len(s) {
+		return 0, false
+	}
+
+	return int(s[index]), true
+}
+
+// ParseSettingsImage validates a settings buffer and returns a copy of it.
+func ParseSettingsImage(buf []byte) (SettingsImage, error) {
+	if len(buf) == 0 {
+		return nil, &ParseError{Frame: "settings", Field: "image", Err: ErrEmptyFrame}
+	}
+
+	if len(buf) > maxSettingsImage {
+		return nil, &ParseError{Frame: "settings", Field: "image", Offset: maxSettingsImage, Err: ErrFrameTooLarge}
+	}
+
+	return append(SettingsImage(nil), buf...), nil
+}
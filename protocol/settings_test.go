@@ -0,0 +1,30 @@
+package protocol
+
+import (
+	"bytes"
+	"testing"
+)
+
+func FuzzParseSettingsImage(f *testing.F) {
+	f.Add([]byte{0, 1, 2, 3})
+	f.Add([]byte{})
+	f.Add(bytes.Repeat([]byte{1}, maxSettingsImage+1))
+
+	f.Fuzz(func(t *testing.T, buf []byte) {
+		image, err := ParseSettingsImage(buf)
+		if err != nil {
+			checkParseError(t, buf, err)
+			return
+		}
+
+		if !bytes.Equal(image, buf) {
+			t.Fatalf("image %v differs from buffer %v", image, buf)
+		}
+
+		// The image must not alias the notification buffer
+		buf[0]++
+		if image[0] == buf[0] {
+			t.Errorf("image shares memory with the buffer")
+		}
+	})
+}
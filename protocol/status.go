@@ -0,0 +1,139 @@
+package protocol
+
+// GPSState is the state of the detector's GPS receiver.
+type GPSState int
+
+const (
+	GPSUnknown GPSState = iota
+	GPSDisconnected
+	GPSConnected
+)
+
+func (s GPSState) String() string {
+	switch s {
+	case GPSDisconnected:
+		return "Disconnected"
+	case GPSConnected:
+		return "Connected"
+	default:
+		return "Unknown"
+	}
+}
+
+func (s GPSState) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+type GPS struct {
+	Heading string
+
+	// Unverified: the second field is assumed to be the speed, in the unit
+	// selected by the "Speed Units" setting. A value that does not parse
+	// leaves it at zero, Raw holds what was received.
+	Speed    float32
+	Altitude float32
+	State    GPSState
+
+	// Every comma-separated field as received
+	Raw []string
+}
+
+type Status struct {
+	Voltage float32
+	Signal  float32
+	GPS     GPS
+
+	// Every "&" separated section as received. The meaning of sections 1
+	// and 3 is unknown, they are only available here.
+	Raw []string
+}
+
+const statusSections = 5
+
+// ParseStatusFrame parses a status frame of five "&" separated sections:
+// voltage, unknown, GPS, unknown, signal. The unknown sections and any past
+// the fifth are kept in Raw.
+func ParseStatusFrame(buf []byte) (Status, error) {
+	if len(buf) == 0 {
+		return Status{}, &ParseError{Frame: "status", Field: "frame", Err: ErrEmptyFrame}
+	}
+
+	sections := split(string(buf), '&', 0)
+	if len(sections) < statusSections {
+		return Status{}, &ParseError{Frame: "status", Field: "sections", Offset: len(buf), Value: string(buf), Err: ErrMissingField}
+	}
+
+	voltage, err := parseFloat32("status", "voltage", sections[0])
+	if err != nil {
+		return Status{}, err
+	}
+
+	// sections[1] is unknown
+	gps, err := parseGPS(sections[2])
+	if err != nil {
+		return Status{}, err
+	}
+
+	// sections[3] is unknown
+	signal, err := parseFloat32("status", "signal", sections[4])
+	if err != nil {
+		return Status{}, err
+	}
+
+	status := Status{
+		Voltage: voltage,
+		GPS:     gps,
+		Signal:  signal,
+	}
+
+	for _, section := range sections {
+		status.Raw = append(status.Raw, section.value)
+	}
+
+	return status, nil
+}
+
+// ParseGPS turns the comma-separated GPS section of a status frame into
+// useful information.
+// 270,55,120,C
+// heading, speed (unverified), altitude, state
+func ParseGPS(data string) (GPS, error) {
+	return parseGPS(field{data, 0})
+}
+
+func parseGPS(section field) (GPS, error) {
+	gpsSections := split(section.value, ',', section.offset)
+	if len(gpsSections) < 4 {
+		end := section.offset + len(section.value)
+		return GPS{}, &ParseError{Frame: "gps", Field: "sections", Offset: end, Value: section.value, Err: ErrMissingField}
+	}
+
+	altitude, err := parseFloat32("gps", "altitude", gpsSections[2])
+	if err != nil {
+		return GPS{}, err
+	}
+
+	// Unverified, so it never fails the frame. Without a fix it may be empty.
+	speed, _ := parseFloat32("gps", "speed", gpsSections[1])
+
+	gps := GPS{
+		Heading:  gpsSections[0].value,
+		Speed:    speed,
+		Altitude: altitude,
+	}
+
+	switch gpsSections[3].value {
+	case "D":
+		gps.State = GPSDisconnected
+	case "C":
+		gps.State = GPSConnected
+	default:
+		gps.State = GPSUnknown
+	}
+
+	for _, f := range gpsSections {
+		gps.Raw = append(gps.Raw, f.value)
+	}
+
+	return gps, nil
+}
@@ -0,0 +1,137 @@
+package protocol
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// No status frame has been captured from a device yet. These frames follow the
+// layout the parser expects and only pin down its behaviour.
+func TestParseStatusFrame(t *testing.T) {
+	tests := []struct {
+		name  string
+		frame string
+		want  Status
+	}{
+		{
+			name:  "GPS connected",
+			frame: "13.8&0&270,55,120,C&0&4.5",
+			want: Status{
+				Voltage: 13.8,
+				Signal:  4.5,
+				GPS: GPS{
+					Heading: "270", Speed: 55, Altitude: 120, State: GPSConnected,
+					Raw: []string{"270", "55", "120", "C"},
+				},
+				Raw: []string{"13.8", "0", "270,55,120,C", "0", "4.5"},
+			},
+		},
+		{
+			name:  "GPS disconnected without speed",
+			frame: "12.1&&,,0,D&&0",
+			want: Status{
+				Voltage: 12.1,
+				GPS: GPS{
+					State: GPSDisconnected,
+					Raw:   []string{"", "", "0", "D"},
+				},
+				Raw: []string{"12.1", "", ",,0,D", "", "0"},
+			},
+		},
+		{
+			name:  "unverified speed does not fail the frame",
+			frame: "13.8&1&N,--,35.5,X&2&3&9",
+			want: Status{
+				Voltage: 13.8,
+				Signal:  3,
+				GPS: GPS{
+					Heading: "N", Altitude: 35.5, State: GPSUnknown,
+					Raw: []string{"N", "--", "35.5", "X"},
+				},
+				Raw: []string{"13.8", "1", "N,--,35.5,X", "2", "3", "9"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ParseStatusFrame([]byte(test.frame))
+			if err != nil {
+				t.Fatalf("ParseStatusFrame(%q): %v", test.frame, err)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("ParseStatusFrame(%q)\n got %+v\nwant %+v", test.frame, got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseStatusFrameErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		frame  string
+		field  string
+		offset int
+		err    error
+	}{
+		{"empty frame", "", "frame", 0, ErrEmptyFrame},
+		{"missing sections", "13.8&0&270,55,120,C", "sections", 19, ErrMissingField},
+		{"invalid voltage", "x&0&270,55,120,C&0&4.5", "voltage", 0, ErrInvalidNumber},
+		{"short GPS section", "13.8&0&270,55&0&4.5", "sections", 13, ErrMissingField},
+		{"invalid altitude", "13.8&0&270,55,high,C&0&4.5", "altitude", 14, ErrInvalidNumber},
+		{"invalid signal", "13.8&0&270,55,120,C&0&", "signal", 22, ErrInvalidNumber},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ParseStatusFrame([]byte(test.frame))
+
+			var perr *ParseError
+			if !errors.As(err, &perr) {
+				t.Fatalf("ParseStatusFrame(%q) = %v, want a ParseError", test.frame, err)
+			}
+
+			if !errors.Is(err, test.err) || perr.Field != test.field || perr.Offset != test.offset {
+				t.Errorf("ParseStatusFrame(%q) = %v, want %v on %s at offset %d", test.frame, err, test.err, test.field, test.offset)
+			}
+		})
+	}
+}
+
+func FuzzParseStatusFrame(f *testing.F) {
+	f.Add([]byte("13.8&0&270,55,120,C&0&4.5"))
+	f.Add([]byte("12.1&&,,0,D&&0"))
+	f.Add([]byte("13.8&0&270,55"))
+
+	f.Fuzz(func(t *testing.T, frame []byte) {
+		status, err := ParseStatusFrame(frame)
+		if err != nil {
+			checkParseError(t, frame, err)
+			return
+		}
+
+		if len(status.Raw) < statusSections {
+			t.Errorf("decoded status with %d sections", len(status.Raw))
+		}
+	})
+}
+
+func FuzzParseGPS(f *testing.F) {
+	f.Add("270,55,120,C")
+	f.Add(",,0,D")
+	f.Add("270,55")
+
+	f.Fuzz(func(t *testing.T, data string) {
+		gps, err := ParseGPS(data)
+		if err != nil {
+			checkParseError(t, []byte(data), err)
+			return
+		}
+
+		if len(gps.Raw) < 4 {
+			t.Errorf("decoded GPS with %d fields", len(gps.Raw))
+		}
+	})
+}
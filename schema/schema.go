@@ -0,0 +1,155 @@
+// Package schema generates a JSON Schema per model for the settings document
+// and TypeScript types for the payloads UnidenInterfaceServer emits, so that
+// clients stop compiling when the Go definitions change.
+package schema
+
+//go:generate go run ../cmd/unidenctl generate -out ../clients
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/smoke7385/smk-uniden-bluetooth/types"
+	"github.com/smoke7385/smk-uniden-bluetooth/uniden"
+)
+
+const jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// SettingsSchema returns the JSON Schema of the settingsUpdate document of a
+// model: an array of serialized settings, each pinned to its key, name and
+// possible values.
+func SettingsSchema(model types.Model) ([]byte, error) {
+	settings := uniden.SettingsForModel(model)
+
+	var items []any
+	for _, setting := range settings {
+		items = append(items, settingSchema(setting, settings))
+	}
+
+	document := map[string]any{
+		"$schema":     jsonSchemaDraft,
+		"$id":         "settings." + string(model) + ".schema.json",
+		"title":       "Uniden " + string(model) + " settings",
+		"description": "settingsUpdate payload, settings definitions revision " + uniden.DefinitionsRevision(),
+		"type":        "array",
+		"items":       map[string]any{"oneOf": items},
+	}
+
+	return json.MarshalIndent(document, "", "  ")
+}
+
+func settingSchema(setting *uniden.Setting, settings uniden.Settings) map[string]any {
+	integer := map[string]any{"type": "integer"}
+	text := map[string]any{"type": "string"}
+
+	return map[string]any{
+		"title":       setting.Name,
+		"description": setting.Description,
+		"type":        "object",
+		"properties": map[string]any{
+			"key":         map[string]any{"const": setting.Key},
+			"name":        map[string]any{"const": setting.Name},
+			"category":    map[string]any{"const": setting.Category},
+			"kind":        map[string]any{"const": setting.Kind},
+			"unit":        text,
+			"description": text,
+			"min":         integer,
+			"max":         integer,
+			"step":        integer,
+			"value":       map[string]any{"enum": valueIDs(setting, settings)},
+			"values":      map[string]any{"type": "string", "contentMediaType": "application/json"},
+		},
+		"required":             []string{"key", "name", "category", "kind", "value", "values"},
+		"additionalProperties": false,
+	}
+}
+
+// valueIDs lists the value IDs a setting can report, as Serialize writes them.
+// Speed settings report the IDs of either speed unit. Serialize reports 0 for
+// a value outside of the table, e.g. before the first settings notification.
+func valueIDs(setting *uniden.Setting, settings uniden.Settings) []string {
+	ids := map[int]bool{0: true}
+
+	collect := func() {
+		for _, value := range *setting.GetValues() {
+			ids[value.ID] = true
+		}
+	}
+
+	units := speedUnits(settings)
+	if setting.Kind == uniden.KindSpeed && units != nil {
+		saved := units.ValueInt
+		for _, value := range units.Values {
+			units.ValueInt = value.ID
+			collect()
+		}
+		units.ValueInt = saved
+	} else {
+		collect()
+	}
+
+	sorted := make([]int, 0, len(ids))
+	for id := range ids {
+		sorted = append(sorted, id)
+	}
+	sort.Ints(sorted)
+
+	enum := make([]string, len(sorted))
+	for i, id := range sorted {
+		enum[i] = strconv.Itoa(id)
+	}
+
+	return enum
+}
+
+func speedUnits(settings uniden.Settings) *uniden.Setting {
+	for _, setting := range settings {
+		if setting.Name == "Speed Units" {
+			return setting
+		}
+	}
+
+	return nil
+}
+
+// Generate writes the TypeScript types and a settings schema per model into
+// dir and returns the paths it wrote.
+func Generate(dir string) ([]string, error) {
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return nil, err
+	}
+
+	var written []string
+
+	write := func(name string, data []byte) error {
+		path := filepath.Join(dir, name)
+		err := os.WriteFile(path, append(data, '\n'), 0o644)
+		if err == nil {
+			written = append(written, path)
+		}
+		return err
+	}
+
+	for _, model := range types.Models {
+		data, err := SettingsSchema(model)
+		if err != nil {
+			return written, err
+		}
+
+		err = write("settings."+string(model)+".schema.json", data)
+		if err != nil {
+			return written, err
+		}
+	}
+
+	err = write("uniden.ts", TypeScript())
+	if err != nil {
+		return written, err
+	}
+
+	return written, nil
+}
@@ -0,0 +1,216 @@
+package schema
+
+import (
+	"bytes"
+	"encoding"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/smoke7385/smk-uniden-bluetooth/protocol"
+	"github.com/smoke7385/smk-uniden-bluetooth/types"
+	"github.com/smoke7385/smk-uniden-bluetooth/uniden"
+)
+
+// Interfaces emitted, in order. Nested structs must be listed as well.
+var interfaces = []struct {
+	name string
+	typ  reflect.Type
+}{
+	{"SerializedSetting", reflect.TypeOf(uniden.SerializedSetting{})},
+	{"SettingValue", reflect.TypeOf(uniden.Value{})},
+	{"RadarEvent", reflect.TypeOf(protocol.RadarEvent{})},
+	{"GPS", reflect.TypeOf(protocol.GPS{})},
+	{"Status", reflect.TypeOf(protocol.Status{})},
+	{"SimulatedCommand", reflect.TypeOf(uniden.SimulatedCommand{})},
+}
+
+// Field types narrower than their Go type, by interface and JSON name
+var fieldOverrides = map[string]string{
+	"SerializedSetting.key":      "SettingKey",
+	"SerializedSetting.category": "SettingCategory",
+	"SerializedSetting.kind":     "SettingKind",
+}
+
+var textMarshaler = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+// TypeScript returns the TypeScript types of the server payloads. Every
+// payload is sent as a JSON string followed by the device address.
+func TypeScript() []byte {
+	var b bytes.Buffer
+
+	fmt.Fprintf(&b, "// Code generated by unidenctl generate. DO NOT EDIT.\n")
+	fmt.Fprintf(&b, "// Settings definitions revision %s\n\n", uniden.DefinitionsRevision())
+
+	var models []string
+	var allKeys []string
+	for _, model := range types.Models {
+		var keys []string
+		for _, setting := range uniden.SettingsForModel(model) {
+			keys = append(keys, setting.Key)
+		}
+
+		fmt.Fprintf(&b, "export type %sSettingKey =%s;\n\n", model, union(keys))
+		models = append(models, string(model))
+		allKeys = append(allKeys, keys...)
+	}
+
+	fmt.Fprintf(&b, "export type Model =%s;\n\n", union(models))
+	fmt.Fprintf(&b, "export type SettingKey =%s;\n\n", union(allKeys))
+
+	var categories []string
+	for _, category := range uniden.Categories {
+		categories = append(categories, string(category))
+	}
+	fmt.Fprintf(&b, "export type SettingCategory =%s;\n\n", union(categories))
+
+	kinds := []string{
+		string(uniden.KindBool),
+		string(uniden.KindEnum),
+		string(uniden.KindRange),
+		string(uniden.KindSpeed),
+		string(uniden.KindTime),
+	}
+	fmt.Fprintf(&b, "export type SettingKind =%s;\n\n", union(kinds))
+
+	var states []string
+	for _, state := range []protocol.GPSState{protocol.GPSUnknown, protocol.GPSDisconnected, protocol.GPSConnected} {
+		states = append(states, state.String())
+	}
+	fmt.Fprintf(&b, "export type GPSState =%s;\n\n", union(states))
+
+	for _, iface := range interfaces {
+		writeInterface(&b, iface.name, iface.typ)
+	}
+
+	b.WriteString(`// Parsed payloads of the server events
+export type SettingsUpdatePayload = SerializedSetting[];
+export type RadarEventPayload = RadarEvent[];
+export type StatusUpdatePayload = Status;
+export type SimulatedCommandPayload = SimulatedCommand;
+
+// Events emitted by the server. Payloads are JSON strings.
+export interface ServerEvents {
+  settingsUpdate: (settings: string, address: string) => void;
+  radarEvent: (alerts: string, address: string) => void;
+  statusUpdate: (status: string, address: string) => void;
+  simulatedCommand: (command: string, address: string) => void;
+}
+`)
+
+	return b.Bytes()
+}
+
+func writeInterface(b *bytes.Buffer, name string, typ reflect.Type) {
+	fmt.Fprintf(b, "export interface %s {\n", name)
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		jsonName, optional, skip := jsonField(field)
+		if skip {
+			continue
+		}
+
+		tsType, ok := fieldOverrides[name+"."+jsonName]
+		if !ok {
+			tsType = typeScriptType(field.Type)
+		}
+
+		if field.Type.Kind() == reflect.Pointer {
+			optional = true
+		}
+
+		marker := ""
+		if optional {
+			marker = "?"
+		}
+
+		fmt.Fprintf(b, "  %s%s: %s;\n", jsonName, marker, tsType)
+	}
+
+	b.WriteString("}\n\n")
+}
+
+// jsonField returns the name encoding/json uses for a field and whether it
+// may be left out.
+func jsonField(field reflect.StructField) (name string, optional bool, skip bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+
+	name, options, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = field.Name
+	}
+
+	return name, strings.Contains(options, "omitempty"), false
+}
+
+func typeScriptType(typ reflect.Type) string {
+	if typ == reflect.TypeOf(protocol.GPSState(0)) {
+		return "GPSState"
+	}
+
+	if typ == reflect.TypeOf(time.Time{}) || typ.Implements(textMarshaler) {
+		return "string"
+	}
+
+	switch typ.Kind() {
+	case reflect.Pointer:
+		return typeScriptType(typ.Elem())
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice:
+		// Nil slices are encoded as null
+		return typeScriptType(typ.Elem()) + "[] | null"
+	case reflect.Struct:
+		for _, iface := range interfaces {
+			if iface.typ == typ {
+				return iface.name
+			}
+		}
+	}
+
+	return "unknown"
+}
+
+// union returns a TypeScript union of string literals, without duplicates,
+// with the space or line break that follows the "=".
+func union(values []string) string {
+	seen := map[string]bool{}
+	var literals []string
+
+	for _, value := range values {
+		if seen[value] {
+			continue
+		}
+		seen[value] = true
+		literals = append(literals, strconv.Quote(value))
+	}
+
+	sort.Strings(literals)
+
+	if len(literals) == 0 {
+		return " never"
+	}
+
+	if len(literals) <= 4 {
+		return " " + strings.Join(literals, " | ")
+	}
+
+	return "\n  | " + strings.Join(literals, "\n  | ")
+}
@@ -0,0 +1,401 @@
+package transport
+
+import (
+	"sync"
+
+	"github.com/smoke7385/smk-uniden-bluetooth/types"
+
+	"tinygo.org/x/bluetooth"
+)
+
+// sharedAdapter is the state shared by every Bluetooth transport on the same
+// adapter, so several detectors can be driven from one radio.
+type sharedAdapter struct {
+	mu         sync.Mutex
+	enabled    bool
+	transports []*Bluetooth
+
+	// Addresses seen while scanning. The native address type differs between
+	// platforms, so Connect can only reach devices that have been scanned.
+	seen     map[string]bluetooth.Address
+	seenRSSI map[string]int16
+
+	// An adapter can only run one scan at a time
+	scanMu   sync.Mutex
+	scanning bool
+}
+
+var (
+	sharedAdapters   = map[*bluetooth.Adapter]*sharedAdapter{}
+	sharedAdaptersMu sync.Mutex
+)
+
+func getSharedAdapter(adapter *bluetooth.Adapter) *sharedAdapter {
+	sharedAdaptersMu.Lock()
+	defer sharedAdaptersMu.Unlock()
+
+	shared, ok := sharedAdapters[adapter]
+	if !ok {
+		shared = &sharedAdapter{
+			seen:     map[string]bluetooth.Address{},
+			seenRSSI: map[string]int16{},
+		}
+		sharedAdapters[adapter] = shared
+	}
+
+	return shared
+}
+
+func (s *sharedAdapter) handleConnectionChange(device bluetooth.Device, connected bool) {
+	s.mu.Lock()
+	transports := append([]*Bluetooth(nil), s.transports...)
+	s.mu.Unlock()
+
+	for _, b := range transports {
+		b.handleConnectionChange(device, connected)
+	}
+}
+
+// Bluetooth is the tinygo backed Transport used on real hardware.
+type Bluetooth struct {
+	adapter *bluetooth.Adapter
+	shared  *sharedAdapter
+	device  *types.Device
+
+	mu            sync.Mutex
+	scanning      bool
+	scanWaiting   bool
+	stopRequested bool
+
+	onDisconnect func()
+}
+
+func NewBluetooth(adapter *bluetooth.Adapter) *Bluetooth {
+	b := &Bluetooth{adapter: adapter, shared: getSharedAdapter(adapter)}
+
+	b.shared.mu.Lock()
+	b.shared.transports = append(b.shared.transports, b)
+	b.shared.mu.Unlock()
+
+	return b
+}
+
+// NewDefaultBluetooth returns a transport on the system's default adapter.
+func NewDefaultBluetooth() *Bluetooth {
+	return NewBluetooth(bluetooth.DefaultAdapter)
+}
+
+func (b *Bluetooth) Enable() error {
+	b.shared.mu.Lock()
+	defer b.shared.mu.Unlock()
+
+	if b.shared.enabled {
+		return nil
+	}
+
+	if err := b.adapter.Enable(); err != nil {
+		return err
+	}
+
+	// Not every platform reports disconnects through the adapter (BlueZ does
+	// not), so callers should not rely on this alone to detect a dead link.
+	b.adapter.SetConnectHandler(b.shared.handleConnectionChange)
+
+	b.shared.enabled = true
+	return nil
+}
+
+func (b *Bluetooth) Scan(callback func(result ScanResult)) error {
+	b.mu.Lock()
+	b.scanWaiting = true
+	b.mu.Unlock()
+
+	b.shared.scanMu.Lock()
+	defer b.shared.scanMu.Unlock()
+
+	b.mu.Lock()
+	b.scanWaiting = false
+	if b.stopRequested {
+		// Stopped while waiting for another transport's scan to finish
+		b.stopRequested = false
+		b.mu.Unlock()
+		return nil
+	}
+	b.scanning = true
+	b.mu.Unlock()
+
+	b.shared.mu.Lock()
+	b.shared.scanning = true
+	b.shared.mu.Unlock()
+
+	defer func() {
+		b.shared.mu.Lock()
+		b.shared.scanning = false
+		b.shared.mu.Unlock()
+
+		b.mu.Lock()
+		b.scanning = false
+		b.stopRequested = false
+		b.mu.Unlock()
+	}()
+
+	return b.adapter.Scan(func(adapter *bluetooth.Adapter, result bluetooth.ScanResult) {
+		b.mu.Lock()
+		stop := b.stopRequested
+		b.mu.Unlock()
+
+		// StopScan may have raced with the scan starting
+		if stop {
+			adapter.StopScan()
+			return
+		}
+
+		b.shared.mu.Lock()
+		b.shared.seen[result.Address.String()] = result.Address
+		b.shared.seenRSSI[result.Address.String()] = result.RSSI
+		b.shared.mu.Unlock()
+
+		callback(ScanResult{
+			Address:   result.Address.String(),
+			LocalName: result.LocalName(),
+			RSSI:      result.RSSI,
+			HasServiceUUID: func(uuid types.CharType) bool {
+				parsed, err := bluetooth.ParseUUID(uuid.String())
+				if err != nil {
+					return false
+				}
+				return result.HasServiceUUID(parsed)
+			},
+		})
+	})
+}
+
+func (b *Bluetooth) StopScan() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.scanWaiting {
+		b.stopRequested = true
+		return nil
+	}
+
+	if !b.scanning {
+		return nil
+	}
+
+	b.stopRequested = true
+	return b.adapter.StopScan()
+}
+
+func (b *Bluetooth) Connect(address string) error {
+	b.shared.mu.Lock()
+	_address, ok := b.shared.seen[address]
+	b.shared.mu.Unlock()
+
+	if !ok {
+		return ErrDeviceNotFound
+	}
+
+	_device, err := b.adapter.Connect(_address, bluetooth.ConnectionParams{})
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	b.device = &types.Device{Device: _device}
+	b.mu.Unlock()
+
+	return nil
+}
+
+func (b *Bluetooth) Discover() ([]types.CharType, error) {
+	device := b.getDevice()
+	if device == nil {
+		return nil, ErrNotConnected
+	}
+
+	srvcs, err := device.DiscoverServices([]bluetooth.UUID{})
+	if err != nil {
+		return nil, err
+	}
+
+	var found []types.CharType
+	for _, _service := range srvcs {
+		service := types.Service{DeviceService: _service}
+
+		characteristics, err := service.DiscoverCharacteristics([]bluetooth.UUID{})
+		if err != nil {
+			continue
+		}
+
+		for _, _char := range characteristics {
+			characteristic := types.Characteristic{DeviceCharacteristic: _char}
+			service.AddCharacteristic(&characteristic)
+			found = append(found, types.CharType(characteristic.UUID().String()))
+		}
+
+		device.AddService(&service)
+	}
+
+	return found, nil
+}
+
+func (b *Bluetooth) Subscribe(uuid types.CharType, callback func(buf []byte)) error {
+	char, err := b.getChar(uuid)
+	if err != nil {
+		return err
+	}
+
+	return char.EnableNotifications(callback)
+}
+
+func (b *Bluetooth) Unsubscribe(uuid types.CharType) error {
+	char, err := b.getChar(uuid)
+	if err != nil {
+		return err
+	}
+
+	return char.EnableNotifications(nil)
+}
+
+func (b *Bluetooth) Write(uuid types.CharType, data []byte) error {
+	char, err := b.getChar(uuid)
+	if err != nil {
+		return err
+	}
+
+	_, err = char.WriteWithoutResponse(data)
+	return err
+}
+
+func (b *Bluetooth) Read(uuid types.CharType) ([]byte, error) {
+	char, err := b.getChar(uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	// The settings image is well under this size for every known model.
+	data := make([]byte, 512)
+	n, err := char.Read(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return data[:n], nil
+}
+
+// MTU is negotiated by the platform stack when connecting, it can only be read.
+func (b *Bluetooth) MTU(uuid types.CharType) (uint16, error) {
+	char, err := b.getChar(uuid)
+	if err != nil {
+		return 0, err
+	}
+
+	return char.GetMTU()
+}
+
+// RSSI returns the strength of the last advertisement seen from the connected
+// device. tinygo does not expose the RSSI of an established link, so the value
+// is only current while a scan is running on the adapter. Otherwise
+// ErrRSSIUnavailable is returned rather than a stale value.
+func (b *Bluetooth) RSSI() (int16, error) {
+	device := b.getDevice()
+	if device == nil {
+		return 0, ErrNotConnected
+	}
+
+	b.shared.mu.Lock()
+	defer b.shared.mu.Unlock()
+
+	if !b.shared.scanning {
+		return 0, ErrRSSIUnavailable
+	}
+
+	rssi, ok := b.shared.seenRSSI[device.Address.String()]
+	if !ok {
+		return 0, ErrRSSIUnavailable
+	}
+
+	return rssi, nil
+}
+
+func (b *Bluetooth) Disconnect() error {
+	device := b.getDevice()
+	if device == nil {
+		return nil
+	}
+
+	b.mu.Lock()
+	b.device = nil
+	b.mu.Unlock()
+
+	return device.Disconnect()
+}
+
+func (b *Bluetooth) SetDisconnectHandler(callback func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.onDisconnect = callback
+}
+
+// Release unregisters the transport from its adapter, so it no longer
+// receives connection changes and can be garbage collected.
+func (b *Bluetooth) Release() {
+	b.shared.mu.Lock()
+	for i, t := range b.shared.transports {
+		if t == b {
+			b.shared.transports = append(b.shared.transports[:i], b.shared.transports[i+1:]...)
+			break
+		}
+	}
+	b.shared.mu.Unlock()
+
+	b.mu.Lock()
+	b.onDisconnect = nil
+	b.mu.Unlock()
+}
+
+func (b *Bluetooth) handleConnectionChange(device bluetooth.Device, connected bool) {
+	if connected {
+		return
+	}
+
+	b.mu.Lock()
+	current := b.device
+	onDisconnect := b.onDisconnect
+	if current != nil && current.Address.String() == device.Address.String() {
+		b.device = nil
+	} else {
+		onDisconnect = nil
+	}
+	b.mu.Unlock()
+
+	if onDisconnect != nil {
+		onDisconnect()
+	}
+}
+
+func (b *Bluetooth) getDevice() *types.Device {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.device
+}
+
+func (b *Bluetooth) getChar(uuid types.CharType) (*types.Characteristic, error) {
+	device := b.getDevice()
+	if device == nil {
+		return nil, ErrNotConnected
+	}
+
+	for _, s := range device.Services {
+		for i := range s.Characteristics {
+			if s.Characteristics[i].UUID().String() == uuid.String() {
+				return &s.Characteristics[i], nil
+			}
+		}
+	}
+
+	return nil, ErrCharacteristicNotFound
+}
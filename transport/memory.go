@@ -0,0 +1,326 @@
+package transport
+
+import (
+	"sync"
+
+	"github.com/smoke7385/smk-uniden-bluetooth/types"
+)
+
+// Advertisement is a device the in-memory transport pretends to see.
+type Advertisement struct {
+	Address   string
+	LocalName string
+	RSSI      int16
+	Services  []types.CharType
+}
+
+// WriteRecord is a single write captured by the in-memory transport.
+type WriteRecord struct {
+	UUID types.CharType
+	Data []byte
+}
+
+// Memory is a Transport that never touches a radio. Tests and simulators
+// seed it with advertisements and characteristic values, push notifications
+// with Notify and inspect what was written with Writes.
+type Memory struct {
+	mu sync.Mutex
+
+	advertisements []Advertisement
+	connected      string
+	stopScan       chan struct{}
+
+	mtu         uint16
+	rssi        int16
+	values      map[types.CharType][]byte
+	subscribers map[types.CharType]func(buf []byte)
+	writes      []WriteRecord
+	onWrite     func(uuid types.CharType, data []byte)
+
+	onDisconnect func()
+}
+
+func NewMemory() *Memory {
+	return &Memory{
+		// Large enough that whole frames pass through unfragmented
+		mtu:         512,
+		rssi:        -60,
+		values:      map[types.CharType][]byte{},
+		subscribers: map[types.CharType]func(buf []byte){},
+	}
+}
+
+// Advertise adds a device that will be reported by Scan.
+func (m *Memory) Advertise(ad Advertisement) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.advertisements = append(m.advertisements, ad)
+}
+
+// SetValue sets the value returned by Read for the given characteristic, and
+// makes the characteristic visible to Discover.
+func (m *Memory) SetValue(uuid types.CharType, data []byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.values[uuid] = append([]byte(nil), data...)
+}
+
+// SetMTU sets the MTU reported for every characteristic.
+func (m *Memory) SetMTU(mtu uint16) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.mtu = mtu
+}
+
+// SetRSSI sets the value returned by RSSI.
+func (m *Memory) SetRSSI(rssi int16) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.rssi = rssi
+}
+
+// Notify delivers data to the subscriber of the given characteristic, as if
+// the device had sent a notification.
+func (m *Memory) Notify(uuid types.CharType, data []byte) error {
+	m.mu.Lock()
+	callback, ok := m.subscribers[uuid]
+	m.mu.Unlock()
+
+	if !ok {
+		return ErrCharacteristicNotFound
+	}
+
+	callback(append([]byte(nil), data...))
+	return nil
+}
+
+// OnWrite registers a hook invoked for every write, allowing a simulator to
+// answer commands.
+func (m *Memory) OnWrite(callback func(uuid types.CharType, data []byte)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.onWrite = callback
+}
+
+// Writes returns every write made since the transport was created.
+func (m *Memory) Writes() []WriteRecord {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]WriteRecord(nil), m.writes...)
+}
+
+func (m *Memory) Enable() error {
+	return nil
+}
+
+func (m *Memory) Scan(callback func(result ScanResult)) error {
+	m.mu.Lock()
+	if m.stopScan != nil {
+		m.mu.Unlock()
+		return ErrAlreadyScanning
+	}
+	stop := make(chan struct{})
+	m.stopScan = stop
+	ads := append([]Advertisement(nil), m.advertisements...)
+	m.mu.Unlock()
+
+	for _, ad := range ads {
+		select {
+		case <-stop:
+			return nil
+		default:
+		}
+
+		services := ad.Services
+		callback(ScanResult{
+			Address:   ad.Address,
+			LocalName: ad.LocalName,
+			RSSI:      ad.RSSI,
+			HasServiceUUID: func(uuid types.CharType) bool {
+				for _, s := range services {
+					if s == uuid {
+						return true
+					}
+				}
+				return false
+			},
+		})
+	}
+
+	<-stop
+	return nil
+}
+
+func (m *Memory) StopScan() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.stopScan != nil {
+		close(m.stopScan)
+		m.stopScan = nil
+	}
+
+	return nil
+}
+
+func (m *Memory) Connect(address string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, ad := range m.advertisements {
+		if ad.Address == address {
+			m.connected = address
+			return nil
+		}
+	}
+
+	return ErrDeviceNotFound
+}
+
+func (m *Memory) Discover() ([]types.CharType, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.connected == "" {
+		return nil, ErrNotConnected
+	}
+
+	var found []types.CharType
+	for uuid := range m.values {
+		found = append(found, uuid)
+	}
+
+	return found, nil
+}
+
+func (m *Memory) Subscribe(uuid types.CharType, callback func(buf []byte)) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.connected == "" {
+		return ErrNotConnected
+	}
+
+	if _, ok := m.values[uuid]; !ok {
+		return ErrCharacteristicNotFound
+	}
+
+	m.subscribers[uuid] = callback
+	return nil
+}
+
+func (m *Memory) Unsubscribe(uuid types.CharType) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.subscribers, uuid)
+	return nil
+}
+
+func (m *Memory) Write(uuid types.CharType, data []byte) error {
+	m.mu.Lock()
+	if m.connected == "" {
+		m.mu.Unlock()
+		return ErrNotConnected
+	}
+
+	if _, ok := m.values[uuid]; !ok {
+		m.mu.Unlock()
+		return ErrCharacteristicNotFound
+	}
+
+	record := WriteRecord{UUID: uuid, Data: append([]byte(nil), data...)}
+	m.writes = append(m.writes, record)
+	onWrite := m.onWrite
+	m.mu.Unlock()
+
+	if onWrite != nil {
+		onWrite(record.UUID, record.Data)
+	}
+
+	return nil
+}
+
+func (m *Memory) Read(uuid types.CharType) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.connected == "" {
+		return nil, ErrNotConnected
+	}
+
+	value, ok := m.values[uuid]
+	if !ok {
+		return nil, ErrCharacteristicNotFound
+	}
+
+	return append([]byte(nil), value...), nil
+}
+
+func (m *Memory) MTU(uuid types.CharType) (uint16, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.connected == "" {
+		return 0, ErrNotConnected
+	}
+
+	return m.mtu, nil
+}
+
+func (m *Memory) RSSI() (int16, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.connected == "" {
+		return 0, ErrNotConnected
+	}
+
+	return m.rssi, nil
+}
+
+func (m *Memory) Disconnect() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.connected = ""
+	m.subscribers = map[types.CharType]func(buf []byte){}
+	return nil
+}
+
+func (m *Memory) SetDisconnectHandler(callback func()) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.onDisconnect = callback
+}
+
+// Release drops the disconnect handler. Memory shares no state with other
+// transports.
+func (m *Memory) Release() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.onDisconnect = nil
+}
+
+// Drop simulates the device going out of range: the link is torn down and the
+// disconnect handler is invoked.
+func (m *Memory) Drop() {
+	m.mu.Lock()
+	wasConnected := m.connected != ""
+	m.connected = ""
+	m.subscribers = map[types.CharType]func(buf []byte){}
+	onDisconnect := m.onDisconnect
+	m.mu.Unlock()
+
+	if wasConnected && onDisconnect != nil {
+		onDisconnect()
+	}
+}
@@ -0,0 +1,69 @@
+package transport
+
+import (
+	"errors"
+
+	"github.com/smoke7385/smk-uniden-bluetooth/types"
+)
+
+var (
+	ErrNotConnected           = errors.New("transport not connected")
+	ErrAlreadyScanning        = errors.New("scan already in progress")
+	ErrCharacteristicNotFound = errors.New("characteristic not found")
+	ErrDeviceNotFound         = errors.New("device not found")
+	ErrRSSIUnavailable        = errors.New("rssi unavailable")
+)
+
+// ScanResult describes a device seen while scanning.
+type ScanResult struct {
+	Address   string
+	LocalName string
+	RSSI      int16
+
+	// HasServiceUUID reports whether the advertisement lists the given service.
+	HasServiceUUID func(uuid types.CharType) bool
+}
+
+// Transport is everything Uniden needs from a BLE stack. The tinygo backend
+// talks to real hardware, the in-memory backend is used for tests and
+// simulators.
+type Transport interface {
+	// Enable prepares the underlying stack. It is safe to call more than once.
+	Enable() error
+
+	// Scan blocks and invokes callback for every advertisement until StopScan
+	// is called.
+	Scan(callback func(result ScanResult)) error
+	StopScan() error
+
+	// Connect connects to the device with the given address.
+	Connect(address string) error
+
+	// Discover walks all services of the connected device and returns the
+	// UUIDs of every characteristic found.
+	Discover() ([]types.CharType, error)
+
+	// Subscribe enables notifications on a characteristic.
+	Subscribe(uuid types.CharType, callback func(buf []byte)) error
+	Unsubscribe(uuid types.CharType) error
+
+	Write(uuid types.CharType, data []byte) error
+	Read(uuid types.CharType) ([]byte, error)
+
+	// MTU reports the ATT MTU negotiated for the characteristic. The payload
+	// of a single notification is at most MTU-3 bytes.
+	MTU(uuid types.CharType) (uint16, error)
+
+	// RSSI samples the signal strength of the connected device.
+	RSSI() (int16, error)
+
+	Disconnect() error
+
+	// SetDisconnectHandler registers a callback invoked when the link to the
+	// connected device drops without Disconnect having been called.
+	SetDisconnectHandler(callback func())
+
+	// Release detaches the transport from any state shared with other
+	// transports. The transport must not be used afterwards.
+	Release()
+}
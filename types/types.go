@@ -1,6 +1,8 @@
 package types
 
 import (
+	"strings"
+
 	"tinygo.org/x/bluetooth"
 )
 
@@ -54,8 +56,26 @@ const (
 	R4 Model = "R4"
 	R8 Model = "R8"
 	R9 Model = "R9"
+
+	// AutoDetect selects the model from the device information service.
+	AutoDetect Model = ""
 )
 
+var Models = []Model{R4, R8, R9}
+
+// ParseModel finds a known model in a model number string such as "R8" or
+// "Uniden R8".
+func ParseModel(modelNumber string) (Model, bool) {
+	upper := strings.ToUpper(modelNumber)
+	for _, model := range Models {
+		if strings.Contains(upper, string(model)) {
+			return model, true
+		}
+	}
+
+	return AutoDetect, false
+}
+
 // Radar bands
 type Band string
 
@@ -83,6 +103,11 @@ type CharsType struct {
 	Command  CharType
 
 	GenericAttribute CharType
+
+	ManufacturerName CharType
+	ModelNumber      CharType
+	SerialNumber     CharType
+	FirmwareRevision CharType
 }
 
 var Characteristics = CharsType{
@@ -97,6 +122,12 @@ var Characteristics = CharsType{
 
 	// Generic channels
 	GenericAttribute: "0000180a-0000-1000-8000-00805f9b34fb",
+
+	// Device information strings
+	ManufacturerName: "00002a29-0000-1000-8000-00805f9b34fb",
+	ModelNumber:      "00002a24-0000-1000-8000-00805f9b34fb",
+	SerialNumber:     "00002a25-0000-1000-8000-00805f9b34fb",
+	FirmwareRevision: "00002a26-0000-1000-8000-00805f9b34fb",
 }
 
 var C = Characteristics
@@ -0,0 +1,170 @@
+package uniden
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/smoke7385/smk-uniden-bluetooth/command"
+)
+
+var (
+	ErrInvalidBatch = errors.New("invalid settings batch")
+	ErrApplyFailed  = errors.New("applying settings failed")
+)
+
+type ApplyStatus int
+
+const (
+	// Not written, validation failed or an earlier setting failed
+	NotApplied ApplyStatus = iota
+	// The device already held the value
+	Unchanged
+	Applied
+	Failed
+	RolledBack
+	RollbackFailed
+)
+
+func (s ApplyStatus) String() string {
+	switch s {
+	case NotApplied:
+		return "Not applied"
+	case Unchanged:
+		return "Unchanged"
+	case Applied:
+		return "Applied"
+	case Failed:
+		return "Failed"
+	case RolledBack:
+		return "Rolled back"
+	case RollbackFailed:
+		return "Rollback failed"
+	}
+
+	return "Unknown"
+}
+
+// ApplyResult is the outcome of one setting in ApplySettings.
+type ApplyResult struct {
+	Name   string
+	Before int
+	Value  int
+	Status ApplyStatus
+	Err    error
+}
+
+// ApplySettings writes a batch of settings, by name, as one unit. Every value
+// is validated before anything is written, against the settings as they will
+// be once the batch is applied. Settings are written in storage index order,
+// settings with dynamic value tables after the ones they depend on, and every
+// write waits for the device to confirm it. If a write fails, the settings
+// already written are restored to their values from before the batch. So is
+// the failed setting, unless the device rejected it: a write that was not
+// confirmed may still have reached the device.
+//
+// The report holds one result per setting in the order they were applied.
+func (m *Uniden) ApplySettings(ctx context.Context, values map[string]int) ([]ApplyResult, error) {
+	target := m.Settings.clone()
+	for i := range target {
+		target[i].Settings = &target
+	}
+
+	var batch []*Setting
+	var invalid error
+
+	for name := range values {
+		setting, err := target.lookup(name, m.Model)
+		if err != nil {
+			invalid = errors.Join(invalid, err)
+			continue
+		}
+
+		setting.ValueInt = values[name]
+		batch = append(batch, setting)
+	}
+
+	sort.Slice(batch, func(i, j int) bool {
+		a, b := batch[i], batch[j]
+		if (a.DynamicValues == nil) != (b.DynamicValues == nil) {
+			return a.DynamicValues == nil
+		}
+		return a.getDeviceStorageIndex() < b.getDeviceStorageIndex()
+	})
+
+	report := make([]ApplyResult, len(batch))
+	for i, setting := range batch {
+		report[i] = ApplyResult{
+			Name:   setting.Name,
+			Before: m.Settings.getByName(setting.Name).ValueInt,
+			Value:  setting.ValueInt,
+		}
+
+		// Validated against target, so speeds use the new speed units
+		err := setting.ValidateValueInt(setting.ValueInt)
+		if err != nil {
+			report[i].Err = err
+			invalid = errors.Join(invalid, fmt.Errorf("%s: %w", setting.Name, err))
+		}
+	}
+
+	if invalid != nil {
+		return report, fmt.Errorf("%w: %w", ErrInvalidBatch, invalid)
+	}
+
+	for i, setting := range batch {
+		if report[i].Before == report[i].Value {
+			report[i].Status = Unchanged
+			continue
+		}
+
+		_, err := m.send(ctx, command.SetSetting(setting.getDeviceStorageIndex(), setting.ValueInt))
+		if err != nil {
+			report[i].Status = Failed
+			report[i].Err = err
+
+			written := i
+			if !errors.Is(err, ErrCommandRejected) {
+				written++
+			}
+
+			m.rollback(batch[:written], report[:written])
+			return report, fmt.Errorf("%w: %s: %w", ErrApplyFailed, setting.Name, err)
+		}
+
+		report[i].Status = Applied
+	}
+
+	return report, nil
+}
+
+// rollback restores applied and failed settings in the order they were
+// applied, so the speed units are back before the speeds that depend on them.
+// It runs for the lifetime of the instance, the caller's context may be what
+// failed.
+func (m *Uniden) rollback(batch []*Setting, report []ApplyResult) {
+	for i, setting := range batch {
+		cmd := command.SetSetting(setting.getDeviceStorageIndex(), report[i].Before)
+
+		var err error
+		switch report[i].Status {
+		case Applied:
+			_, err = m.send(m.lifetime, cmd)
+		case Failed:
+			// The settings image cannot tell whether the failed write landed
+			_, err = m.resend(m.lifetime, cmd)
+		default:
+			continue
+		}
+
+		if err != nil {
+			report[i].Status = RollbackFailed
+			report[i].Err = errors.Join(report[i].Err, err)
+			m.println("Failed to roll back", setting.Name, err.Error())
+			continue
+		}
+
+		report[i].Status = RolledBack
+	}
+}
@@ -0,0 +1,83 @@
+package uniden
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/smoke7385/smk-uniden-bluetooth/types"
+)
+
+// applyPair connects to a fake device and returns two settings, the second
+// written after the first by ApplySettings.
+func applyPair(t *testing.T) (*fakeDevice, *Uniden, *Setting, *Setting) {
+	t.Helper()
+
+	d := newFakeDevice(t, types.R4)
+	u := d.connect(t, types.R4)
+	u.Queue.Timeout = 200 * time.Millisecond
+	u.Queue.Retries = 0
+
+	first, second := u.Settings.getByName("X Band"), u.Settings.getByName("K Band")
+	if first.getDeviceStorageIndex() > second.getDeviceStorageIndex() {
+		first, second = second, first
+	}
+
+	return d, u, first, second
+}
+
+func TestApplySettingsRollsBackUnconfirmedWrite(t *testing.T) {
+	d, u, first, second := applyPair(t)
+	firstBefore, secondBefore := first.ValueInt, second.ValueInt
+
+	// The device takes the value but never reports it
+	d.silenced[second.getDeviceStorageIndex()] = true
+
+	report, err := u.ApplySettings(context.Background(), map[string]int{
+		first.Name:  otherValue(first),
+		second.Name: otherValue(second),
+	})
+	if !errors.Is(err, ErrApplyFailed) || !errors.Is(err, ErrNotConfirmed) {
+		t.Fatalf("ApplySettings = %v, want ErrApplyFailed and ErrNotConfirmed", err)
+	}
+
+	for _, result := range report {
+		if result.Status != RolledBack {
+			t.Errorf("%s: %s, want %s", result.Name, result.Status, RolledBack)
+		}
+	}
+
+	if v := d.value(first.getDeviceStorageIndex()); v != firstBefore {
+		t.Errorf("device holds %s = %d, want %d", first.Name, v, firstBefore)
+	}
+	if v := d.value(second.getDeviceStorageIndex()); v != secondBefore {
+		t.Errorf("device holds %s = %d, want %d", second.Name, v, secondBefore)
+	}
+}
+
+func TestApplySettingsKeepsRejectedWrite(t *testing.T) {
+	d, u, first, second := applyPair(t)
+	secondBefore := second.ValueInt
+
+	d.rejected[second.getDeviceStorageIndex()] = true
+
+	report, err := u.ApplySettings(context.Background(), map[string]int{
+		first.Name:  otherValue(first),
+		second.Name: otherValue(second),
+	})
+	if !errors.Is(err, ErrCommandRejected) {
+		t.Fatalf("ApplySettings = %v, want ErrCommandRejected", err)
+	}
+
+	want := []ApplyStatus{RolledBack, Failed}
+	for i, result := range report {
+		if result.Status != want[i] {
+			t.Errorf("%s: %s, want %s", result.Name, result.Status, want[i])
+		}
+	}
+
+	if v := d.value(second.getDeviceStorageIndex()); v != secondBefore {
+		t.Errorf("device holds %s = %d, want %d", second.Name, v, secondBefore)
+	}
+}
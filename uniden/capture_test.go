@@ -0,0 +1,79 @@
+package uniden
+
+import (
+	"bytes"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/smoke7385/smk-uniden-bluetooth/capture"
+	"github.com/smoke7385/smk-uniden-bluetooth/types"
+)
+
+// lockedBuffer is a bytes.Buffer safe to write from notification goroutines.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return append([]byte(nil), b.buf.Bytes()...)
+}
+
+func TestCaptureDiffsFragmentedSettingsImage(t *testing.T) {
+	d := newFakeDevice(t, types.R4)
+	d.mem.SetMTU(DefaultATTMTU)
+
+	var log lockedBuffer
+	xBandValues := make(chan int, 4)
+
+	u := d.connect(t, types.R4, func(u *Uniden) {
+		u.Capture(&log)
+		u.OnSettingsChange(func(s Settings) { xBandValues <- s.getByName("X Band").ValueInt })
+	})
+
+	before, err := u.SettingsImage()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	xBand := u.Settings.getByName("X Band")
+	xBandValue := otherValue(xBand)
+
+	after := append([]byte(nil), before...)
+	after[xBand.getDeviceStorageIndex()] = byte(xBandValue)
+
+	// Notified in MTU sized fragments, as the device does at the default MTU
+	payload := DefaultATTMTU - attHeaderSize
+	for start := 0; start < len(after); start += payload {
+		d.notify(types.C.Settings, after[start:min(start+payload, len(after))])
+	}
+
+	for receive(t, xBandValues) != xBandValue {
+	}
+
+	records, err := capture.ReadAll(bytes.NewReader(log.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	image, ok := capture.LastSettingsImage(records)
+	if !ok || !bytes.Equal(image, after) {
+		t.Fatalf("captured settings image %v, want %v", image, after)
+	}
+
+	changes := DiffSettingsImages(types.R4, before, image)
+	if len(changes) != 1 || changes[0].Index != xBand.getDeviceStorageIndex() || !slices.Contains(changes[0].Settings, "X Band") {
+		t.Errorf("diff %+v, want only X Band at %d", changes, xBand.getDeviceStorageIndex())
+	}
+}
@@ -0,0 +1,98 @@
+package uniden
+
+import (
+	"context"
+	"errors"
+
+	"github.com/smoke7385/smk-uniden-bluetooth/command"
+	"github.com/smoke7385/smk-uniden-bluetooth/protocol"
+)
+
+var (
+	ErrNoResponse      = errors.New("no response from device")
+	ErrNotConfirmed    = errors.New("device did not confirm the setting")
+	ErrCommandRejected = errors.New("command rejected by device")
+)
+
+type Response = protocol.Response
+
+// pendingCommand is a command waiting for its reply on the response
+// characteristic. Replies are matched to commands by verb, oldest first.
+type pendingCommand struct {
+	verb  string
+	reply chan Response
+}
+
+func (m *Uniden) OnResponse(callback func(r Response)) {
+	m.onResponse = callback
+}
+
+// SendCommand validates a command against the settings table and queues it.
+// It returns once the device has confirmed the command, see QueueConfig. A
+// rejection is returned as ErrCommandRejected, no confirmation after every
+// retry as ErrNoResponse or ErrNotConfirmed. In dry run mode the command is
+// simulated and accepted at once.
+func (m *Uniden) SendCommand(ctx context.Context, cmd command.Command) (Response, error) {
+	err := cmd.Validate(&m.Settings)
+	if err != nil {
+		return Response{}, err
+	}
+
+	return m.send(ctx, cmd)
+}
+
+// send is SendCommand for callers that have validated the command against the
+// setting they mean to write.
+func (m *Uniden) send(ctx context.Context, cmd command.Command) (Response, error) {
+	if m.DryRun() {
+		m.simulate(cmd.String())
+		return Response{Verb: string(cmd.Verb), Status: protocol.ResponseOK}, nil
+	}
+
+	return m.enqueue(ctx, cmd, false)
+}
+
+// resend is send for a setting the device may hold another value of than it
+// reported, such as after a write that was not confirmed. The command is
+// written and confirmed even if the last settings image holds the value.
+func (m *Uniden) resend(ctx context.Context, cmd command.Command) (Response, error) {
+	if m.DryRun() {
+		return m.send(ctx, cmd)
+	}
+
+	return m.enqueue(ctx, cmd, true)
+}
+
+// sendCommand is SendCommand bounded by the lifetime of the instance. The
+// queue bounds every attempt, so it does not need a timeout of its own.
+func (m *Uniden) sendCommand(cmd command.Command) (Response, error) {
+	return m.SendCommand(m.lifetime, cmd)
+}
+
+func (m *Uniden) dropPendingCommand(pending *pendingCommand) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, p := range m.pendingCommands {
+		if p == pending {
+			m.pendingCommands = append(m.pendingCommands[:i], m.pendingCommands[i+1:]...)
+			return
+		}
+	}
+}
+
+// resolveCommand hands a reply to the oldest command with the same verb.
+func (m *Uniden) resolveCommand(response Response) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, p := range m.pendingCommands {
+		if p.verb == response.Verb {
+			m.pendingCommands = append(m.pendingCommands[:i], m.pendingCommands[i+1:]...)
+			p.reply <- response
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,100 @@
+package uniden
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/smoke7385/smk-uniden-bluetooth/command"
+	"github.com/smoke7385/smk-uniden-bluetooth/protocol"
+	"github.com/smoke7385/smk-uniden-bluetooth/transport"
+	"github.com/smoke7385/smk-uniden-bluetooth/types"
+)
+
+func newDryRun(t *testing.T, model types.Model) *Uniden {
+	t.Helper()
+
+	u, err := NewUnidenWithTransport(model, transport.NewMemory())
+	if err != nil {
+		t.Fatal(err)
+	}
+	u.Verbose = false
+	u.SetDryRun(true)
+
+	return u
+}
+
+func TestUpdateSettingValidatesNamedSetting(t *testing.T) {
+	u := newDryRun(t, types.R8)
+
+	// Band colors have more values than the other color settings
+	if err := u.UpdateSetting("Ka band color", 8); err != nil {
+		t.Fatalf("UpdateSetting: %v", err)
+	}
+
+	ka := u.Settings.getByName("Ka band color")
+	want := command.SetSetting(ka.getDeviceStorageIndex(), 8).String()
+
+	commands := u.SimulatedCommands()
+	if len(commands) != 1 || commands[0].Command != want {
+		t.Errorf("simulated %+v, want %s", commands, want)
+	}
+}
+
+func TestSendCommandRejectsSharedIndex(t *testing.T) {
+	u := newDryRun(t, types.R4)
+
+	scanIcon := u.Settings.getByName("Scan icon")
+	if scanIcon == nil {
+		t.Skip("no setting shares a storage index")
+	}
+
+	_, err := u.SendCommand(context.Background(), command.SetSetting(scanIcon.getDeviceStorageIndex(), 0))
+	if !errors.Is(err, ErrAmbiguousStorageIndex) {
+		t.Errorf("SendCommand = %v, want ErrAmbiguousStorageIndex", err)
+	}
+}
+
+func TestUnparsedResponseIsPassedOn(t *testing.T) {
+	d := newFakeDevice(t, types.R4)
+
+	responses := make(chan Response, 1)
+	d.connect(t, types.R4, func(u *Uniden) {
+		u.OnResponse(func(r Response) { responses <- r })
+		u.OnError(func(err error) { t.Errorf("unparsed response reported as error: %v", err) })
+	})
+
+	d.notify(types.C.Response, []byte("OK"))
+
+	response := receive(t, responses)
+	if response.Status != protocol.ResponseUnparsed || response.Raw != "OK" {
+		t.Errorf("response %+v, want the raw reply as unparsed", response)
+	}
+}
+
+func TestOnlySetSettingIsRetried(t *testing.T) {
+	d := newFakeDevice(t, types.R4)
+
+	var writes atomic.Int32
+	d.mem.OnWrite(func(uuid types.CharType, data []byte) {
+		if cmd, err := command.ParseCommand(data); err == nil && cmd.Verb == "TEST" {
+			writes.Add(1)
+		}
+		d.handleWrite(uuid, data)
+	})
+
+	u := d.connect(t, types.R4)
+	u.Queue.Timeout = 50 * time.Millisecond
+	u.Queue.Retries = 2
+
+	_, err := u.SendCommand(context.Background(), command.Raw("TEST", ""))
+	if !errors.Is(err, ErrNoResponse) {
+		t.Fatalf("SendCommand = %v, want ErrNoResponse", err)
+	}
+
+	if n := writes.Load(); n != 1 {
+		t.Errorf("command written %d times, want once", n)
+	}
+}
@@ -0,0 +1,293 @@
+package uniden
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/smoke7385/smk-uniden-bluetooth/types"
+)
+
+var (
+	ErrScanTimeout           = errors.New("scan timeout")
+	ErrNoServices            = errors.New("no services identified")
+	ErrCharacteristicMissing = errors.New("characteristic missing")
+	ErrReconnectAborted      = errors.New("reconnect aborted")
+	ErrClosed                = errors.New("uniden closed")
+)
+
+// ConnectOptions tunes a single connection attempt. Zero fields take their
+// value from DefaultConnectOptions.
+type ConnectOptions struct {
+	ScanTimeout time.Duration
+
+	// Characteristics the device must expose for the connection to succeed.
+	// Use an empty, non-nil slice to require none.
+	RequiredCharacteristics []types.CharType
+}
+
+var DefaultConnectOptions = ConnectOptions{
+	ScanTimeout: 10 * time.Second,
+	RequiredCharacteristics: []types.CharType{
+		types.C.Settings,
+		types.C.Command,
+	},
+}
+
+// withDefaults fills the zero fields of o from DefaultConnectOptions.
+func (o ConnectOptions) withDefaults() ConnectOptions {
+	if o.ScanTimeout <= 0 {
+		o.ScanTimeout = DefaultConnectOptions.ScanTimeout
+	}
+	if o.RequiredCharacteristics == nil {
+		o.RequiredCharacteristics = DefaultConnectOptions.RequiredCharacteristics
+	}
+
+	return o
+}
+
+// ConnectionState is the state of the link supervisor.
+type ConnectionState int
+
+const (
+	Idle ConnectionState = iota
+	Scanning
+	Connecting
+	Discovering
+	Syncing
+	Ready
+	Backoff
+)
+
+func (s ConnectionState) String() string {
+	switch s {
+	case Idle:
+		return "Idle"
+	case Scanning:
+		return "Scanning"
+	case Connecting:
+		return "Connecting"
+	case Discovering:
+		return "Discovering"
+	case Syncing:
+		return "Syncing"
+	case Ready:
+		return "Ready"
+	case Backoff:
+		return "Backoff"
+	default:
+		return "Unknown"
+	}
+}
+
+// ReconnectPolicy controls how the supervisor retries after the link drops.
+type ReconnectPolicy struct {
+	Enabled        bool
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+	// MaxAttempts of 0 retries forever.
+	MaxAttempts int
+}
+
+var DefaultReconnectPolicy = ReconnectPolicy{
+	Enabled:        true,
+	InitialBackoff: 1 * time.Second,
+	MaxBackoff:     30 * time.Second,
+	Multiplier:     2,
+	MaxAttempts:    0,
+}
+
+func (p ReconnectPolicy) next(backoff time.Duration) time.Duration {
+	next := time.Duration(float64(backoff) * p.Multiplier)
+	if next > p.MaxBackoff {
+		return p.MaxBackoff
+	}
+	return next
+}
+
+func (m *Uniden) State() ConnectionState {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.state
+}
+
+func (m *Uniden) OnConnectionStateChange(callback func(from ConnectionState, to ConnectionState)) {
+	m.onConnectionStateChange = callback
+}
+
+func (m *Uniden) setState(state ConnectionState) {
+	m.mu.Lock()
+	previous := m.state
+	m.state = state
+	m.mu.Unlock()
+
+	if previous == state {
+		return
+	}
+
+	m.println("Connection state:", previous.String(), "->", state.String())
+
+	if m.onConnectionStateChange != nil {
+		(m.onConnectionStateChange)(previous, state)
+	}
+}
+
+// handleLinkLost is invoked by the transport when the device drops off without
+// Disconnect having been called.
+func (m *Uniden) handleLinkLost() {
+	m.mu.Lock()
+	if m.closing || m.reconnecting || m.state == Idle {
+		m.mu.Unlock()
+		return
+	}
+	m.reconnecting = true
+	m.wg.Add(1)
+	m.mu.Unlock()
+
+	m.println("Lost connection to device:", m.address)
+	m.stopWatchdog()
+
+	if m.onDisconnect != nil {
+		(m.onDisconnect)()
+	}
+
+	if !m.ReconnectPolicy.Enabled {
+		m.mu.Lock()
+		m.reconnecting = false
+		m.mu.Unlock()
+
+		m.wg.Done()
+		m.setState(Idle)
+		return
+	}
+
+	go m.reconnect()
+}
+
+// reconnect retries the connection with exponential backoff until it succeeds,
+// the policy gives up or Disconnect is called.
+func (m *Uniden) reconnect() error {
+	defer m.wg.Done()
+	defer func() {
+		m.mu.Lock()
+		m.reconnecting = false
+		m.mu.Unlock()
+	}()
+
+	policy := m.ReconnectPolicy
+	backoff := policy.InitialBackoff
+
+	for attempt := 1; policy.MaxAttempts == 0 || attempt <= policy.MaxAttempts; attempt++ {
+		m.setState(Backoff)
+
+		select {
+		case <-time.After(backoff):
+		case <-m.lifetime.Done():
+			return ErrReconnectAborted
+		}
+
+		m.mu.Lock()
+		closing := m.closing
+		m.mu.Unlock()
+
+		if closing {
+			return ErrReconnectAborted
+		}
+
+		m.println("Reconnect attempt", attempt, "to", m.address)
+
+		m.mu.Lock()
+		opts := m.connectOptions
+		m.mu.Unlock()
+
+		err := m.establish(m.lifetime, m.address, opts)
+		if err == nil {
+			return nil
+		}
+
+		m.println("Reconnect failed:", err)
+		m.transport.Disconnect()
+		backoff = policy.next(backoff)
+	}
+
+	m.setState(Idle)
+	return ErrReconnectAborted
+}
+
+// connectContext connects the transport to address, giving up when ctx is
+// done. A connect that still succeeds after being abandoned is torn down again,
+// and the next attempt waits for that before connecting.
+func (m *Uniden) connectContext(ctx context.Context, address string) error {
+	m.mu.Lock()
+	abandoned := m.abandonedConnect
+	m.mu.Unlock()
+
+	if abandoned != nil {
+		select {
+		case <-abandoned:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- m.transport.Connect(address)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+	}
+
+	cleaned := make(chan struct{})
+	m.mu.Lock()
+	m.abandonedConnect = cleaned
+	tracked := !m.closed
+	if tracked {
+		m.wg.Add(1)
+	}
+	m.mu.Unlock()
+
+	go func() {
+		if tracked {
+			defer m.wg.Done()
+		}
+		defer close(cleaned)
+
+		if <-done == nil {
+			m.println("Dropping connection that completed after the attempt was abandoned:", address)
+			m.transport.Disconnect()
+		}
+	}()
+
+	return ctx.Err()
+}
+
+// runContext runs a blocking transport call, returning early with the context
+// error if ctx is done first. The call itself keeps running in the background
+// until the transport gives up; callers tear the connection down afterwards.
+func runContext(ctx context.Context, call func() error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- call()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
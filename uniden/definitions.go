@@ -0,0 +1,300 @@
+package uniden
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/smoke7385/smk-uniden-bluetooth/types"
+)
+
+// DefinitionsVersion is the definitions file format this package reads.
+const DefinitionsVersion = 1
+
+var (
+	ErrDefinitionsVersion = errors.New("unsupported definitions version")
+	ErrInvalidDefinition  = errors.New("invalid setting definition")
+)
+
+// Settings definitions shipped with the package
+//
+//go:embed definitions/settings.json
+var embeddedDefinitions []byte
+
+// Definitions is the settings definitions file. Version is the file format,
+// Revision identifies the data so a patched file can be told apart.
+type Definitions struct {
+	Version     int                          `json:"version"`
+	Revision    string                       `json:"revision"`
+	ValueTables map[string][]ValueDefinition `json:"valueTables"`
+	Settings    []SettingDefinition          `json:"settings"`
+}
+
+type ValueDefinition struct {
+	Name string `json:"name"`
+	ID   int    `json:"id"`
+}
+
+// SettingDefinition describes one setting. Its values come from exactly one
+// of Values, Table (a shared value table), Range or Speed.
+//
+// Kind is derived from the values when left out: bool for the boolean
+// table, range, speed, and enum for everything else.
+//
+// Default is a value ID like the ones stored on the device, not a display
+// value. A range counts its steps from 0, so a 30-100% slider in steps of 10
+// defaults to 100% with 7.
+type SettingDefinition struct {
+	Key         string              `json:"key"`
+	Name        string              `json:"name"`
+	Category    Category            `json:"category"`
+	Kind        Kind                `json:"kind,omitempty"`
+	Unit        string              `json:"unit,omitempty"`
+	Description string              `json:"description,omitempty"`
+	Index       map[types.Model]int `json:"index"`
+	Values      []ValueDefinition   `json:"values,omitempty"`
+	Table       string              `json:"table,omitempty"`
+	Range       *RangeDefinition    `json:"range,omitempty"`
+	Speed       *SpeedDefinition    `json:"speed,omitempty"`
+	Default     any                 `json:"default,omitempty"`
+}
+
+// RangeDefinition is a slider, values are numbered from 0 in steps.
+type RangeDefinition struct {
+	Min    int    `json:"min"`
+	Max    int    `json:"max"`
+	Step   int    `json:"step"`
+	Suffix string `json:"suffix,omitempty"`
+}
+
+// SpeedDefinition is a speed setting whose values follow the Speed Units
+// setting.
+type SpeedDefinition struct {
+	MPH RangeDefinition `json:"mph"`
+	KPH RangeDefinition `json:"kph"`
+
+	// Value IDs are the speeds themselves instead of their position
+	Literal bool `json:"literal,omitempty"`
+
+	// Values listed before the speeds, such as Off
+	Prefix []ValueDefinition `json:"prefix,omitempty"`
+}
+
+var (
+	definitionsMu       sync.RWMutex
+	definitionsRevision string
+	defSettings         = mustParseDefinitions(embeddedDefinitions)
+)
+
+func mustParseDefinitions(data []byte) Settings {
+	defs, err := ParseDefinitions(data)
+	if err != nil {
+		panic(fmt.Sprintf("embedded settings definitions: %s", err))
+	}
+
+	settings, err := defs.Build()
+	if err != nil {
+		panic(fmt.Sprintf("embedded settings definitions: %s", err))
+	}
+
+	definitionsRevision = defs.Revision
+	return settings
+}
+
+// definitions returns the settings table new instances are built from.
+func definitions() Settings {
+	definitionsMu.RLock()
+	defer definitionsMu.RUnlock()
+
+	return defSettings
+}
+
+// DefinitionsRevision returns the revision of the definitions in use.
+func DefinitionsRevision() string {
+	definitionsMu.RLock()
+	defer definitionsMu.RUnlock()
+
+	return definitionsRevision
+}
+
+// SettingsForModel returns a detached settings table for model, as a new
+// instance for that model starts with.
+func SettingsForModel(model types.Model) Settings {
+	return newSettingsSnapshot(model)
+}
+
+func ParseDefinitions(data []byte) (*Definitions, error) {
+	var defs Definitions
+
+	err := json.Unmarshal(data, &defs)
+	if err != nil {
+		return nil, err
+	}
+
+	if defs.Version != DefinitionsVersion {
+		return nil, fmt.Errorf("%w: %d", ErrDefinitionsVersion, defs.Version)
+	}
+
+	return &defs, nil
+}
+
+// LoadDefinitions replaces the embedded definitions with the file at path.
+// Instances created afterwards use the new definitions, existing ones keep
+// the table they were built with.
+func LoadDefinitions(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	defs, err := ParseDefinitions(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	settings, err := defs.Build()
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	definitionsMu.Lock()
+	defer definitionsMu.Unlock()
+
+	defSettings = settings
+	definitionsRevision = defs.Revision
+
+	return nil
+}
+
+// Build turns the definitions into a settings table.
+func (d *Definitions) Build() (Settings, error) {
+	settings := make(Settings, 0, len(d.Settings))
+
+	for _, def := range d.Settings {
+		setting, err := d.buildSetting(def)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidDefinition, def.Name, err)
+		}
+
+		settings = append(settings, setting)
+	}
+
+	return settings, nil
+}
+
+func (d *Definitions) buildSetting(def SettingDefinition) (*Setting, error) {
+	if def.Name == "" {
+		return nil, errors.New("missing name")
+	}
+
+	setting := &Setting{
+		Name:         def.Name,
+		StorageIndex: def.Index,
+		DefaultValue: normalizeDefault(def.Default),
+		Key:          def.Key,
+		Category:     def.Category,
+		Kind:         def.Kind,
+		Unit:         def.Unit,
+		Description:  def.Description,
+	}
+
+	sources := 0
+	kind := KindEnum
+
+	if def.Values != nil {
+		sources++
+		setting.Values = toValues(def.Values)
+	}
+
+	if def.Table != "" {
+		sources++
+		table, ok := d.ValueTables[def.Table]
+		if !ok {
+			return nil, fmt.Errorf("unknown value table %q", def.Table)
+		}
+		setting.Values = toValues(table)
+
+		if def.Table == "boolean" {
+			kind = KindBool
+		}
+	}
+
+	if r := def.Range; r != nil {
+		sources++
+		if r.Step <= 0 {
+			return nil, fmt.Errorf("range step %d", r.Step)
+		}
+		setting.Values = generateSlidersRange(r.Min, r.Max, r.Step, r.Suffix)
+		setting.rangeDef = r
+		kind = KindRange
+	}
+
+	if sp := def.Speed; sp != nil {
+		sources++
+		if sp.MPH.Step <= 0 || sp.KPH.Step <= 0 {
+			return nil, errors.New("speed step must be positive")
+		}
+		setting.DynamicValues = speedValues(*sp)
+		setting.speedDef = sp
+		kind = KindSpeed
+	}
+
+	if sources != 1 {
+		return nil, fmt.Errorf("needs exactly one of values, table, range or speed, has %d", sources)
+	}
+
+	switch setting.Kind {
+	case "":
+		setting.Kind = kind
+	case KindBool, KindEnum, KindRange, KindSpeed, KindTime:
+	default:
+		return nil, fmt.Errorf("unknown kind %q", setting.Kind)
+	}
+
+	return setting, nil
+}
+
+func speedValues(sp SpeedDefinition) func(s *Settings) *Values {
+	prefix := toValues(sp.Prefix)
+
+	return func(s *Settings) *Values {
+		var speeds *Values
+		if sp.Literal {
+			speeds = getSpeedValuesLiteral(s, sp.MPH.Min, sp.MPH.Max, sp.MPH.Step, sp.KPH.Min, sp.KPH.Max, sp.KPH.Step)
+		} else {
+			speeds = getSpeedValues(s, sp.MPH.Min, sp.MPH.Max, sp.MPH.Step, sp.KPH.Min, sp.KPH.Max, sp.KPH.Step)
+		}
+
+		if len(prefix) == 0 {
+			return speeds
+		}
+
+		values := append(append(Values{}, prefix...), *speeds...)
+		return &values
+	}
+}
+
+func toValues(defs []ValueDefinition) Values {
+	if defs == nil {
+		return nil
+	}
+
+	values := make(Values, len(defs))
+	for i, def := range defs {
+		values[i] = Value{Name: def.Name, ID: def.ID}
+	}
+
+	return values
+}
+
+// normalizeDefault turns JSON numbers back into ints.
+func normalizeDefault(value any) any {
+	if f, ok := value.(float64); ok && f == float64(int(f)) {
+		return int(f)
+	}
+
+	return value
+}
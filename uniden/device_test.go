@@ -0,0 +1,198 @@
+package uniden
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/smoke7385/smk-uniden-bluetooth/command"
+	"github.com/smoke7385/smk-uniden-bluetooth/transport"
+	"github.com/smoke7385/smk-uniden-bluetooth/types"
+)
+
+const fakeAddress = "00:11:22:33:44:55"
+
+type notification struct {
+	uuid types.CharType
+	data []byte
+}
+
+// fakeDevice answers SETC writes on an in-memory transport the way a detector
+// does: by notifying the updated settings image. Notifications are delivered
+// one at a time on a single goroutine, as BlueZ does.
+type fakeDevice struct {
+	mem *transport.Memory
+
+	mu            sync.Mutex
+	image         []byte
+	notifications chan notification
+
+	// Storage indices whose next write is applied but not notified, and
+	// whose writes are rejected
+	silenced map[int]bool
+	rejected map[int]bool
+}
+
+func newFakeDevice(t *testing.T, model types.Model) *fakeDevice {
+	t.Helper()
+
+	d := &fakeDevice{
+		mem:           transport.NewMemory(),
+		image:         defaultImage(t, model),
+		notifications: make(chan notification, 16),
+		silenced:      map[int]bool{},
+		rejected:      map[int]bool{},
+	}
+
+	d.mem.Advertise(transport.Advertisement{Address: fakeAddress, LocalName: string(model)})
+	d.mem.SetValue(types.C.ModelNumber, []byte(model))
+	d.mem.SetValue(types.C.Settings, d.image)
+	d.mem.SetValue(types.C.Status, nil)
+	d.mem.SetValue(types.C.RadarEvent, nil)
+	d.mem.SetValue(types.C.Response, nil)
+	d.mem.SetValue(types.C.Command, nil)
+	d.mem.OnWrite(d.handleWrite)
+
+	done := make(chan struct{})
+	t.Cleanup(func() { close(done) })
+
+	go func() {
+		for {
+			select {
+			case n := <-d.notifications:
+				d.mem.Notify(n.uuid, n.data)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return d
+}
+
+// connect returns a Uniden connected to the device, closed with the test.
+// setup runs before connecting, to install callbacks.
+func (d *fakeDevice) connect(t *testing.T, model types.Model, setup ...func(u *Uniden)) *Uniden {
+	t.Helper()
+
+	u, err := NewUnidenWithTransport(model, d.mem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u.Verbose = false
+	u.ReconnectPolicy.Enabled = false
+
+	for _, f := range setup {
+		f(u)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := u.ConnectContext(ctx, fakeAddress, DefaultConnectOptions); err != nil {
+		t.Fatalf("ConnectContext: %v", err)
+	}
+
+	t.Cleanup(func() { u.Close(context.Background()) })
+
+	return u
+}
+
+func (d *fakeDevice) notify(uuid types.CharType, data []byte) {
+	d.notifications <- notification{uuid, append([]byte(nil), data...)}
+}
+
+func (d *fakeDevice) handleWrite(uuid types.CharType, data []byte) {
+	if uuid != types.C.Command {
+		return
+	}
+
+	cmd, err := command.ParseCommand(data)
+	if err != nil || cmd.Verb != command.VerbSetSetting {
+		return
+	}
+
+	d.mu.Lock()
+	if d.rejected[cmd.Index] {
+		d.mu.Unlock()
+		d.notify(types.C.Response, []byte("BTrspSETC:NG"))
+		return
+	}
+
+	for len(d.image) <= cmd.Index {
+		d.image = append(d.image, 0)
+	}
+	d.image[cmd.Index] = byte(cmd.Value)
+	image := append([]byte(nil), d.image...)
+	silenced := d.silenced[cmd.Index]
+	delete(d.silenced, cmd.Index)
+	d.mu.Unlock()
+
+	if !silenced {
+		d.notify(types.C.Settings, image)
+	}
+}
+
+// value returns the byte the device holds at a storage index.
+func (d *fakeDevice) value(index int) int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return int(d.image[index])
+}
+
+// otherValue returns a valid value the setting does not hold.
+func otherValue(s *Setting) int {
+	for _, v := range *s.GetValues() {
+		if v.ID != s.ValueInt {
+			return v.ID
+		}
+	}
+
+	return s.ValueInt
+}
+
+// A conditional callback runs on a settings notification, and writing a
+// setting from it waits for another one.
+func TestConditionalCallbackUpdatesSetting(t *testing.T) {
+	d := newFakeDevice(t, types.R4)
+	u := d.connect(t, types.R4, func(u *Uniden) {
+		u.OnSettingsChange(func(s Settings) {})
+	})
+	u.Queue.Timeout = 500 * time.Millisecond
+	u.Queue.Retries = 0
+
+	volume := u.Settings.getByName("Detector volume")
+	xBand := u.Settings.getByName("X Band")
+	target := otherValue(volume)
+	xBandTarget := otherValue(xBand)
+
+	done := make(chan error, 1)
+	u.RegisterConditionalCallback(
+		func(u *Uniden) bool { return volume.ValueInt == target },
+		func(u *Uniden) error {
+			err := xBand.Update(xBandTarget)
+			done <- err
+			return err
+		},
+		5*time.Second,
+	)
+
+	if err := volume.Update(target); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("update from the callback: %v", err)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("conditional callback did not finish")
+	}
+
+	if xBand.ValueInt != xBandTarget {
+		t.Errorf("X Band = %d, want %d", xBand.ValueInt, xBandTarget)
+	}
+}
@@ -0,0 +1,107 @@
+package uniden
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/smoke7385/smk-uniden-bluetooth/protocol"
+	"github.com/smoke7385/smk-uniden-bluetooth/types"
+)
+
+// DeviceInfo holds the strings from the device information service (0x180a).
+type DeviceInfo struct {
+	Manufacturer     string
+	ModelNumber      string
+	SerialNumber     string
+	FirmwareRevision string
+}
+
+var (
+	ErrModelMismatch = errors.New("device model does not match the requested model")
+	ErrUnknownModel  = errors.New("could not determine device model")
+)
+
+// readDeviceInfo reads the device information strings and selects the model
+// and its decoder. A model passed to NewUniden is checked against the device
+// rather than overridden.
+func (m *Uniden) readDeviceInfo() error {
+	err := m.detectModel()
+	if err != nil {
+		return err
+	}
+
+	// A firmware specific decoder wins over the model default
+	decoder, err := protocol.Lookup(m.Model, m.DeviceInfo.FirmwareRevision)
+	if err != nil {
+		return err
+	}
+
+	m.decoder = decoder
+	return nil
+}
+
+func (m *Uniden) detectModel() error {
+	info := DeviceInfo{
+		Manufacturer:     m.readString(types.C.ManufacturerName),
+		ModelNumber:      m.readString(types.C.ModelNumber),
+		SerialNumber:     m.readString(types.C.SerialNumber),
+		FirmwareRevision: m.readString(types.C.FirmwareRevision),
+	}
+
+	m.DeviceInfo = info
+	m.println("Device info:", info.Manufacturer, info.ModelNumber, info.FirmwareRevision, info.SerialNumber)
+
+	detected, ok := types.ParseModel(info.ModelNumber)
+
+	if m.requestedModel == types.AutoDetect {
+		if !ok {
+			return fmt.Errorf("%w from model number %q", ErrUnknownModel, info.ModelNumber)
+		}
+
+		m.setModel(detected)
+		return nil
+	}
+
+	// The device did not report a usable model, trust the caller.
+	if !ok {
+		return nil
+	}
+
+	if detected != m.requestedModel {
+		return fmt.Errorf("%w: requested %s, device reports %s", ErrModelMismatch, m.requestedModel, detected)
+	}
+
+	return nil
+}
+
+func (m *Uniden) readString(uuid types.CharType) string {
+	data, err := m.transport.Read(uuid)
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimRight(string(data), "\x00 ")
+}
+
+// setModel binds the instance to a model, rebuilding the settings table when
+// the model changes so it only holds settings the model has.
+func (m *Uniden) setModel(model types.Model) {
+	if m.Model == model && m.Settings != nil {
+		return
+	}
+
+	m.Model = model
+	defs := definitions()
+	m.Settings = defs.forModel(model)
+	for i := range m.Settings {
+		m.Settings[i].Settings = &m.Settings
+		m.Settings[i].Uniden = m
+		m.Settings[i].Model = model
+	}
+}
+
+// SupportedSettings returns the settings the model has, for UIs to list.
+func (m *Uniden) SupportedSettings() Settings {
+	return append(Settings(nil), m.Settings...)
+}
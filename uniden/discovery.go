@@ -0,0 +1,127 @@
+package uniden
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/smoke7385/smk-uniden-bluetooth/transport"
+	"github.com/smoke7385/smk-uniden-bluetooth/types"
+)
+
+// Candidate is a nearby device that advertises at least one Uniden service.
+type Candidate struct {
+	Address      string
+	LocalName    string
+	RSSI         int16
+	ServiceUUIDs []types.CharType
+}
+
+var ErrNoCandidates = errors.New("no uniden devices found")
+
+// Device information (0x180a) is advertised by plenty of other hardware, so
+// only the Uniden specific UUIDs are used to pick out detectors.
+var discoveryUUIDs = []types.CharType{
+	types.C.RadarEvent,
+	types.C.Settings,
+	types.C.Status,
+	types.C.Response,
+	types.C.Command,
+}
+
+// Discover scans the default Bluetooth adapter for the given duration and
+// returns every Uniden detector seen, strongest signal first.
+func Discover(ctx context.Context, timeout time.Duration) ([]Candidate, error) {
+	t := transport.NewDefaultBluetooth()
+	defer t.Release()
+
+	return DiscoverWith(ctx, t, timeout)
+}
+
+// DiscoverWith is Discover on an explicit transport. The transport stays
+// registered; release it once it is no longer needed.
+func DiscoverWith(ctx context.Context, t transport.Transport, timeout time.Duration) ([]Candidate, error) {
+	if err := t.Enable(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var mu sync.Mutex
+	found := map[string]Candidate{}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- t.Scan(func(result transport.ScanResult) {
+			var uuids []types.CharType
+			for _, uuid := range discoveryUUIDs {
+				if result.HasServiceUUID != nil && result.HasServiceUUID(uuid) {
+					uuids = append(uuids, uuid)
+				}
+			}
+
+			if len(uuids) == 0 {
+				return
+			}
+
+			mu.Lock()
+			found[result.Address] = Candidate{
+				Address:      result.Address,
+				LocalName:    result.LocalName,
+				RSSI:         result.RSSI,
+				ServiceUUIDs: uuids,
+			}
+			mu.Unlock()
+		})
+	}()
+
+	select {
+	case <-ctx.Done():
+		t.StopScan()
+		<-done
+	case err := <-done:
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	candidates := make([]Candidate, 0, len(found))
+	for _, c := range found {
+		candidates = append(candidates, c)
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].RSSI > candidates[j].RSSI
+	})
+
+	// A cancelled parent context is an error, running out the scan window is not.
+	if errors.Is(ctx.Err(), context.Canceled) && len(candidates) == 0 {
+		return nil, ctx.Err()
+	}
+
+	return candidates, nil
+}
+
+// ConnectFirst discovers nearby detectors and connects to the one with the
+// strongest signal.
+func (m *Uniden) ConnectFirst(ctx context.Context, timeout time.Duration) (Candidate, error) {
+	candidates, err := DiscoverWith(ctx, m.transport, timeout)
+	if err != nil {
+		return Candidate{}, err
+	}
+
+	if len(candidates) == 0 {
+		return Candidate{}, ErrNoCandidates
+	}
+
+	candidate := candidates[0]
+	m.println("Connecting to strongest candidate:", candidate.Address, candidate.LocalName, candidate.RSSI)
+
+	return candidate, m.ConnectContext(ctx, candidate.Address, DefaultConnectOptions)
+}
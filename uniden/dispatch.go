@@ -0,0 +1,52 @@
+package uniden
+
+// dispatch queues work that calls back into user code. Callbacks run in order
+// on a goroutine of their own, so a callback may block, or write a setting and
+// wait for the notification that confirms it, without holding up the
+// notification goroutine. Callbacks queued after Close are dropped.
+func (m *Uniden) dispatch(callback func()) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return
+	}
+
+	m.callbacks = append(m.callbacks, callback)
+
+	if m.callbacksReady == nil {
+		m.callbacksReady = make(chan struct{}, 1)
+		m.wg.Add(1)
+		go m.runDispatcher(m.callbacksReady)
+	}
+
+	select {
+	case m.callbacksReady <- struct{}{}:
+	default:
+	}
+}
+
+func (m *Uniden) runDispatcher(ready chan struct{}) {
+	defer m.wg.Done()
+
+	for {
+		select {
+		case <-m.lifetime.Done():
+			return
+		case <-ready:
+		}
+
+		for {
+			m.mu.Lock()
+			if len(m.callbacks) == 0 {
+				m.mu.Unlock()
+				break
+			}
+			callback := m.callbacks[0]
+			m.callbacks = m.callbacks[1:]
+			m.mu.Unlock()
+
+			callback()
+		}
+	}
+}
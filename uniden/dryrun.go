@@ -0,0 +1,117 @@
+package uniden
+
+import (
+	"time"
+
+	"github.com/smoke7385/smk-uniden-bluetooth/command"
+)
+
+// SimulatedCommand is a command that dry run mode kept from the device.
+type SimulatedCommand struct {
+	Command string `json:"command"`
+	Verb    string `json:"verb,omitempty"`
+
+	// Settings at the storage index, comma-separated where several share it
+	Setting string    `json:"setting,omitempty"`
+	Value   int       `json:"value"`
+	Time    time.Time `json:"time"`
+}
+
+// SetDryRun turns dry run mode on or off. In dry run mode nothing is written
+// to the device: commands are recorded, reported through OnSimulatedCommand
+// and SETC writes are applied to Settings as if the device had confirmed
+// them. Settings notifications from the device keep the simulated values on
+// top. Turning dry run off discards the simulated values and the record, and
+// Settings goes back to what the device last reported.
+func (m *Uniden) SetDryRun(enabled bool) {
+	m.mu.Lock()
+	wasEnabled := m.dryRun
+	m.dryRun = enabled
+	image := m.settingsImage
+
+	if !enabled {
+		m.simulated = nil
+		m.simulatedLog = nil
+	}
+	m.mu.Unlock()
+
+	if wasEnabled && !enabled {
+		m.settingsChanged(m.storeSettings(image, nil))
+	}
+}
+
+func (m *Uniden) DryRun() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.dryRun
+}
+
+// SimulatedCommands returns the commands recorded since dry run was turned on.
+func (m *Uniden) SimulatedCommands() []SimulatedCommand {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]SimulatedCommand(nil), m.simulatedLog...)
+}
+
+func (m *Uniden) OnSimulatedCommand(callback func(c SimulatedCommand)) {
+	m.onSimulatedCommand = callback
+}
+
+// simulatedValues returns a copy of the simulated values, nil outside of dry
+// run. Called with m.mu held.
+func (m *Uniden) simulatedValues() map[int]int {
+	if !m.dryRun {
+		return nil
+	}
+
+	values := make(map[int]int, len(m.simulated))
+	for index, value := range m.simulated {
+		values[index] = value
+	}
+
+	return values
+}
+
+// simulate records a command instead of writing it.
+func (m *Uniden) simulate(raw string) {
+	simulated := SimulatedCommand{Command: raw, Time: time.Now()}
+
+	var changedSettings Settings
+
+	cmd, err := command.ParseCommand([]byte(raw))
+	if err == nil {
+		simulated.Verb = string(cmd.Verb)
+
+		if cmd.Verb == command.VerbSetSetting {
+			simulated.Value = cmd.Value
+			simulated.Setting = settingNames(m.Settings.getAllByDeviceStorageIndex(cmd.Index))
+
+			m.mu.Lock()
+			if m.simulated == nil {
+				m.simulated = map[int]int{}
+			}
+			m.simulated[cmd.Index] = cmd.Value
+			m.mu.Unlock()
+
+			changedSettings = m.storeSettings(nil, map[int]int{cmd.Index: cmd.Value})
+		}
+	}
+
+	m.mu.Lock()
+	m.simulatedLog = append(m.simulatedLog, simulated)
+	m.mu.Unlock()
+
+	m.println("Dry run, not sending:", raw)
+
+	if m.onSimulatedCommand != nil {
+		(m.onSimulatedCommand)(simulated)
+	}
+
+	if m.server != nil {
+		m.server.handleSimulatedCommand(m, simulated)
+	}
+
+	m.settingsChanged(changedSettings)
+}
@@ -0,0 +1,157 @@
+package uniden
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/smoke7385/smk-uniden-bluetooth/types"
+)
+
+const (
+	// DefaultATTMTU is the MTU every BLE link starts with.
+	DefaultATTMTU = 23
+
+	// Bytes of each ATT packet taken up by the opcode and handle.
+	attHeaderSize = 3
+
+	// Largest frame buffered before giving up on it.
+	maxFrameSize = 2048
+)
+
+var (
+	ErrTruncatedFrame = errors.New("truncated frame")
+	ErrFrameTooLarge  = errors.New("frame too large")
+)
+
+// frameRecorder is implemented by transports that log reassembled frames, such
+// as the capture transport.
+type frameRecorder interface {
+	RecordFrame(uuid types.CharType, frame []byte)
+}
+
+// DefaultFrameTimeout is how long a partial frame waits for its next fragment.
+var DefaultFrameTimeout = 250 * time.Millisecond
+
+// frameAssembler rebuilds frames that the device split over several
+// notifications. A notification that fills the whole MTU payload is assumed to
+// be followed by another fragment; anything shorter ends the frame. Only frames
+// passing the completeness check are handed on.
+type frameAssembler struct {
+	mu          sync.Mutex
+	uuid        types.CharType
+	payloadSize int
+	timeout     time.Duration
+	buf         []byte
+	timer       *time.Timer
+
+	complete func(frame []byte) bool
+	onFrame  func(frame []byte)
+	onError  func(err error)
+}
+
+func newFrameAssembler(uuid types.CharType, mtu uint16, onFrame func(frame []byte), onError func(err error)) *frameAssembler {
+	if mtu <= attHeaderSize {
+		mtu = DefaultATTMTU
+	}
+
+	return &frameAssembler{
+		uuid:        uuid,
+		payloadSize: int(mtu) - attHeaderSize,
+		timeout:     DefaultFrameTimeout,
+		complete:    frameCompleteness(uuid),
+		onFrame:     onFrame,
+		onError:     onError,
+	}
+}
+
+// push takes a single notification.
+func (f *frameAssembler) push(chunk []byte) {
+	f.mu.Lock()
+
+	if f.timer != nil {
+		f.timer.Stop()
+		f.timer = nil
+	}
+
+	f.buf = append(f.buf, chunk...)
+
+	if len(f.buf) > maxFrameSize {
+		f.buf = nil
+		f.mu.Unlock()
+		f.onError(fmt.Errorf("%w on %s", ErrFrameTooLarge, f.uuid))
+		return
+	}
+
+	// A full notification, more may follow
+	if len(chunk) >= f.payloadSize {
+		f.timer = time.AfterFunc(f.timeout, f.flush)
+		f.mu.Unlock()
+		return
+	}
+
+	f.mu.Unlock()
+	f.flush()
+}
+
+func (f *frameAssembler) flush() {
+	f.mu.Lock()
+	frame := f.buf
+	f.buf = nil
+	f.timer = nil
+	f.mu.Unlock()
+
+	if len(frame) == 0 {
+		return
+	}
+
+	if !f.complete(frame) {
+		f.onError(fmt.Errorf("%w on %s: %q", ErrTruncatedFrame, f.uuid, frame))
+		return
+	}
+
+	f.onFrame(frame)
+}
+
+// frameCompleteness returns the structural check for frames on uuid. Binary
+// channels have no structure to check.
+func frameCompleteness(uuid types.CharType) func(frame []byte) bool {
+	switch uuid {
+	case types.C.RadarEvent:
+		return radarFrameComplete
+	case types.C.Status:
+		return statusFrameComplete
+	default:
+		return func(frame []byte) bool { return true }
+	}
+}
+
+// Every slot is either "0" or at least eight comma-separated fields ending in a
+// non-empty one. Only the boundaries are checked, extra fields are left to the
+// decoder since some firmware appends them.
+func radarFrameComplete(frame []byte) bool {
+	for _, slot := range strings.Split(string(frame), "&") {
+		if slot == "0" {
+			continue
+		}
+
+		fields := strings.Split(slot, ",")
+		if len(fields) < 8 || fields[len(fields)-1] == "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Five sections, the third of which holds four GPS fields.
+func statusFrameComplete(frame []byte) bool {
+	sections := strings.Split(string(frame), "&")
+	if len(sections) < 5 || sections[4] == "" {
+		return false
+	}
+
+	return len(strings.Split(sections[2], ",")) >= 4
+}
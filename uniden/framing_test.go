@@ -0,0 +1,115 @@
+package uniden
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/smoke7385/smk-uniden-bluetooth/types"
+)
+
+type assembled struct {
+	frame []byte
+	err   error
+}
+
+// chunks splits data into notifications of at most size bytes.
+func chunks(data string, size int) [][]byte {
+	var out [][]byte
+	for len(data) > 0 {
+		n := min(size, len(data))
+		out = append(out, []byte(data[:n]))
+		data = data[n:]
+	}
+
+	return out
+}
+
+func TestFrameAssembler(t *testing.T) {
+	const radarFrame = "1,00,K,5,123,24.1090,0,1"
+	payload := DefaultATTMTU - attHeaderSize
+
+	tests := []struct {
+		name   string
+		uuid   types.CharType
+		chunks [][]byte
+		frame  string
+		err    error
+	}{
+		{
+			name:   "chunks are joined until a short one",
+			uuid:   types.C.Settings,
+			chunks: chunks(string(bytes.Repeat([]byte{1}, 2*payload+5)), payload),
+			frame:  string(bytes.Repeat([]byte{1}, 2*payload+5)),
+		},
+		{
+			name:   "radar frame over two notifications",
+			uuid:   types.C.RadarEvent,
+			chunks: chunks(radarFrame, payload),
+			frame:  radarFrame,
+		},
+		{
+			name:   "a chunk filling the MTU is flushed by the timeout",
+			uuid:   types.C.Settings,
+			chunks: [][]byte{bytes.Repeat([]byte{2}, payload)},
+			frame:  string(bytes.Repeat([]byte{2}, payload)),
+		},
+		{
+			name:   "truncated radar frame",
+			uuid:   types.C.RadarEvent,
+			chunks: [][]byte{[]byte("1,00,K,5,123")},
+			err:    ErrTruncatedFrame,
+		},
+		{
+			name:   "truncated status frame",
+			uuid:   types.C.Status,
+			chunks: [][]byte{[]byte("1&2&3,4")},
+			err:    ErrTruncatedFrame,
+		},
+		{
+			name:   "frame over maxFrameSize",
+			uuid:   types.C.Settings,
+			chunks: chunks(string(bytes.Repeat([]byte{3}, (maxFrameSize/payload+1)*payload)), payload),
+			err:    ErrFrameTooLarge,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			results := make(chan assembled, len(test.chunks)+1)
+
+			f := newFrameAssembler(test.uuid, DefaultATTMTU,
+				func(frame []byte) { results <- assembled{frame: frame} },
+				func(err error) { results <- assembled{err: err} },
+			)
+			f.timeout = 10 * time.Millisecond
+
+			for _, chunk := range test.chunks {
+				f.push(chunk)
+			}
+
+			var got assembled
+			select {
+			case got = <-results:
+			case <-time.After(time.Second):
+				t.Fatal("no frame or error")
+			}
+
+			if test.err != nil {
+				if !errors.Is(got.err, test.err) {
+					t.Errorf("got %q, %v, want %v", got.frame, got.err, test.err)
+				}
+			} else if got.err != nil || string(got.frame) != test.frame {
+				t.Errorf("got %q, %v, want %q", got.frame, got.err, test.frame)
+			}
+
+			// Nothing else may follow, not even after the timeout
+			select {
+			case extra := <-results:
+				t.Errorf("unexpected %q, %v", extra.frame, extra.err)
+			case <-time.After(5 * f.timeout):
+			}
+		})
+	}
+}
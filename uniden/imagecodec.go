@@ -0,0 +1,205 @@
+package uniden
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/smoke7385/smk-uniden-bluetooth/command"
+	"github.com/smoke7385/smk-uniden-bluetooth/protocol"
+	"github.com/smoke7385/smk-uniden-bluetooth/types"
+)
+
+var (
+	ErrNoSettingsImage = errors.New("no settings image received yet")
+	ErrValueOutOfRange = errors.New("value does not fit in a settings byte")
+	ErrPartialRestore  = errors.New("settings image only partly restored")
+)
+
+// SettingsBackup is a settings image together with the model it was taken
+// from. Models lay out their images differently, so a backup only restores
+// onto the same model.
+type SettingsBackup struct {
+	Model types.Model `json:"model"`
+	Image []byte      `json:"image"`
+}
+
+// DecodeSettingsImage returns a detached settings snapshot for a model,
+// filled from a raw settings image. Settings stored past the end of the image
+// are left at value ID 0, which is not necessarily their default. The snapshot is not bound to a device, calling
+// Update on it fails.
+func DecodeSettingsImage(model types.Model, image []byte) (Settings, error) {
+	parsed, err := protocol.ParseSettingsImage(image)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := newSettingsSnapshot(model)
+
+	for _, setting := range snapshot {
+		index, ok := setting.StorageIndex[model]
+		if !ok {
+			continue
+		}
+
+		value, ok := parsed.Value(index)
+		if !ok {
+			continue
+		}
+
+		setting.ValueInt = value
+	}
+
+	return snapshot, nil
+}
+
+// EncodeSettingsImage renders settings into the raw image for a model. Bytes
+// without a known setting are taken from base, so encoding a decoded image
+// gives back the same bytes. The image grows to cover every known index when
+// base is shorter.
+func EncodeSettingsImage(model types.Model, settings Settings, base []byte) ([]byte, error) {
+	image := append([]byte(nil), base...)
+
+	for _, setting := range settings {
+		index, ok := setting.StorageIndex[model]
+		if !ok {
+			continue
+		}
+
+		if setting.ValueInt < 0 || setting.ValueInt > 0xff {
+			return nil, fmt.Errorf("%w: %s = %d", ErrValueOutOfRange, setting.Name, setting.ValueInt)
+		}
+
+		for len(image) <= index {
+			image = append(image, 0)
+		}
+
+		image[index] = byte(setting.ValueInt)
+	}
+
+	return image, nil
+}
+
+func newSettingsSnapshot(model types.Model) Settings {
+	defs := definitions()
+	snapshot := defs.forModel(model)
+	for i := range snapshot {
+		snapshot[i].Settings = &snapshot
+		snapshot[i].Uniden = nil
+		snapshot[i].Model = model
+	}
+
+	return snapshot
+}
+
+// SettingsImage returns a copy of the last settings image the device sent,
+// for a byte-for-byte backup.
+func (m *Uniden) SettingsImage() ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.settingsImage == nil {
+		return nil, ErrNoSettingsImage
+	}
+
+	return append([]byte(nil), m.settingsImage...), nil
+}
+
+// BackupSettings returns the last settings image the device sent, recorded
+// with its model for RestoreSettingsImage.
+func (m *Uniden) BackupSettings() (SettingsBackup, error) {
+	image, err := m.SettingsImage()
+	if err != nil {
+		return SettingsBackup{}, err
+	}
+
+	return SettingsBackup{Model: m.Model, Image: image}, nil
+}
+
+// SettingsSnapshot decodes the last settings image into detached settings
+// that are not changed by later notifications.
+func (m *Uniden) SettingsSnapshot() (Settings, error) {
+	image, err := m.SettingsImage()
+	if err != nil {
+		return nil, err
+	}
+
+	return DecodeSettingsImage(m.Model, image)
+}
+
+// RestoreSettingsImage writes a backup onto the device, which must be of the
+// model the backup was taken from. The protocol has no known bulk write, so
+// every changed byte with a known setting is written with its own SETC
+// command. Changed bytes without a known setting cannot be written; they are
+// returned together with ErrPartialRestore.
+//
+// Every write is validated before the first is sent, so an invalid backup
+// leaves the device untouched. Settings with value tables that depend on
+// other settings, such as speeds depending on the speed units, are written
+// last.
+func (m *Uniden) RestoreSettingsImage(ctx context.Context, backup SettingsBackup) ([]ImageChange, error) {
+	if backup.Model != m.Model {
+		return nil, fmt.Errorf("%w: backup of %s, device is %s", ErrModelMismatch, backup.Model, m.Model)
+	}
+
+	image := backup.Image
+	target, err := DecodeSettingsImage(m.Model, image)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := m.SettingsImage()
+	if err != nil {
+		return nil, err
+	}
+
+	var writes []*Setting
+	var unmapped []ImageChange
+
+	for _, change := range DiffSettingsImages(m.Model, current, image) {
+		if change.After < 0 {
+			continue
+		}
+
+		if len(change.Settings) == 0 {
+			unmapped = append(unmapped, change)
+			continue
+		}
+
+		setting, err := target.getByDeviceStorageIndex(change.Index)
+		if err != nil {
+			unmapped = append(unmapped, change)
+			continue
+		}
+
+		writes = append(writes, setting)
+	}
+
+	sort.SliceStable(writes, func(i, j int) bool {
+		return writes[i].DynamicValues == nil && writes[j].DynamicValues != nil
+	})
+
+	var invalid []error
+	for _, setting := range writes {
+		if err := setting.ValidateValueInt(setting.ValueInt); err != nil {
+			invalid = append(invalid, fmt.Errorf("restoring %s: %w", setting.Name, err))
+		}
+	}
+	if len(invalid) > 0 {
+		return unmapped, errors.Join(invalid...)
+	}
+
+	for _, setting := range writes {
+		_, err := m.send(ctx, command.SetSetting(setting.getDeviceStorageIndex(), setting.ValueInt))
+		if err != nil {
+			return unmapped, fmt.Errorf("restoring %s: %w", setting.Name, err)
+		}
+	}
+
+	if len(unmapped) > 0 {
+		return unmapped, fmt.Errorf("%w: %d changed bytes without a known setting", ErrPartialRestore, len(unmapped))
+	}
+
+	return unmapped, nil
+}
@@ -0,0 +1,103 @@
+package uniden
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/smoke7385/smk-uniden-bluetooth/types"
+)
+
+// defaultImage is the settings image of a fresh snapshot of a model. Build
+// does not apply DefaultValue, so every known setting is at value ID 0.
+func defaultImage(t *testing.T, model types.Model) []byte {
+	t.Helper()
+
+	image, err := EncodeSettingsImage(model, newSettingsSnapshot(model), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return image
+}
+
+func TestRestoreSettingsImageChecksModel(t *testing.T) {
+	u := newDryRun(t, types.R4)
+	u.handleSettingsUpdate(defaultImage(t, types.R4), types.C.Settings)
+
+	backup := SettingsBackup{Model: types.R8, Image: defaultImage(t, types.R8)}
+
+	_, err := u.RestoreSettingsImage(context.Background(), backup)
+	if !errors.Is(err, ErrModelMismatch) {
+		t.Errorf("RestoreSettingsImage = %v, want ErrModelMismatch", err)
+	}
+
+	if commands := u.SimulatedCommands(); len(commands) != 0 {
+		t.Errorf("wrote %+v to a device of another model", commands)
+	}
+}
+
+func TestRestoreSettingsImagePartial(t *testing.T) {
+	u := newDryRun(t, types.R4)
+	image := defaultImage(t, types.R4)
+	u.handleSettingsUpdate(image, types.C.Settings)
+
+	backup, err := u.BackupSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// One byte with a known setting and one past every known setting
+	volume := u.Settings.getByName("Detector volume")
+	index := volume.getDeviceStorageIndex()
+	values := *volume.GetValues()
+	backup.Image[index] = byte(values[1].ID)
+	if backup.Image[index] == image[index] {
+		backup.Image[index] = byte(values[0].ID)
+	}
+
+	unmappedIndex := len(backup.Image)
+	backup.Image = append(backup.Image, 1)
+
+	unmapped, err := u.RestoreSettingsImage(context.Background(), backup)
+	if !errors.Is(err, ErrPartialRestore) {
+		t.Fatalf("RestoreSettingsImage = %v, want ErrPartialRestore", err)
+	}
+
+	if len(unmapped) != 1 || unmapped[0].Index != unmappedIndex {
+		t.Errorf("unmapped %+v, want index %d", unmapped, unmappedIndex)
+	}
+
+	if volume.ValueInt != int(backup.Image[index]) {
+		t.Errorf("Detector volume = %d, want %d", volume.ValueInt, backup.Image[index])
+	}
+}
+
+func TestRestoreSettingsImageValidatesFirst(t *testing.T) {
+	u := newDryRun(t, types.R4)
+	image := defaultImage(t, types.R4)
+	u.handleSettingsUpdate(image, types.C.Settings)
+
+	backup, err := u.BackupSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A valid change that would be written first, and an invalid speed that
+	// would be written last
+	volume := u.Settings.getByName("Detector volume")
+	values := *volume.GetValues()
+	backup.Image[volume.getDeviceStorageIndex()] = byte(values[len(values)-1].ID)
+
+	speed := u.Settings.getByName("Quiet Ride Speed")
+	backup.Image[speed.getDeviceStorageIndex()] = 0xff
+
+	_, err = u.RestoreSettingsImage(context.Background(), backup)
+	if err == nil {
+		t.Fatal("RestoreSettingsImage accepted a speed outside its value table")
+	}
+
+	if commands := u.SimulatedCommands(); len(commands) != 0 {
+		t.Errorf("sent %+v before validating the whole backup", commands)
+	}
+}
@@ -0,0 +1,64 @@
+package uniden
+
+import (
+	"github.com/smoke7385/smk-uniden-bluetooth/types"
+)
+
+// ImageChange is a storage index whose byte differs between two settings
+// images. Before or After is -1 when the index is past the end of an image.
+type ImageChange struct {
+	Index  int
+	Before int
+	After  int
+
+	// Known settings stored at this index for the model, empty if unmapped
+	Settings []string
+}
+
+// DiffSettingsImages compares two raw settings images and names the known
+// settings behind every changed byte. It is meant for mapping storage
+// indices that are not in the settings table yet.
+func DiffSettingsImages(model types.Model, before []byte, after []byte) []ImageChange {
+	var changes []ImageChange
+
+	length := len(before)
+	if len(after) > length {
+		length = len(after)
+	}
+
+	for index := 0; index < length; index++ {
+		b, a := imageByte(before, index), imageByte(after, index)
+		if b == a {
+			continue
+		}
+
+		changes = append(changes, ImageChange{
+			Index:    index,
+			Before:   b,
+			After:    a,
+			Settings: settingNamesAt(model, index),
+		})
+	}
+
+	return changes
+}
+
+func imageByte(image []byte, index int) int {
+	if index >= len(image) {
+		return -1
+	}
+
+	return int(image[index])
+}
+
+func settingNamesAt(model types.Model, index int) []string {
+	var names []string
+
+	for _, setting := range definitions() {
+		if si, ok := setting.StorageIndex[model]; ok && si == index {
+			names = append(names, setting.Name)
+		}
+	}
+
+	return names
+}
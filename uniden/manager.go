@@ -0,0 +1,250 @@
+package uniden
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/smoke7385/smk-uniden-bluetooth/transport"
+	"github.com/smoke7385/smk-uniden-bluetooth/types"
+)
+
+// EventKind identifies what a ManagerEvent carries.
+type EventKind string
+
+const (
+	RadarEventKind      EventKind = "radar"
+	StatusEventKind     EventKind = "status"
+	SettingsEventKind   EventKind = "settings"
+	ConnectionEventKind EventKind = "connection"
+	SimulatedEventKind  EventKind = "simulated"
+)
+
+// ManagerEvent is an event from one of the managed devices, tagged with the
+// address of the device it came from.
+type ManagerEvent struct {
+	Address string
+	Kind    EventKind
+	Time    time.Time
+
+	Alerts   []RadarEvent
+	Status   Status
+	Settings Settings
+	State    ConnectionState
+	Command  SimulatedCommand
+}
+
+// Manager owns several Uniden instances, one per detector, keyed by address.
+// It installs its own callbacks on every instance it owns; listen with
+// OnEvent instead of the per-instance callbacks.
+type Manager struct {
+	mu      sync.Mutex
+	devices map[string]*Uniden
+	server  *UnidenInterfaceServer
+
+	newTransport func() transport.Transport
+	onEvent      func(e ManagerEvent)
+
+	Verbose bool
+}
+
+// NewManager creates a Manager whose devices share the default Bluetooth
+// adapter.
+func NewManager() *Manager {
+	return NewManagerWithTransport(func() transport.Transport {
+		return transport.NewDefaultBluetooth()
+	})
+}
+
+// NewManagerWithTransport creates a Manager that builds a transport for every
+// device it adds with newTransport.
+func NewManagerWithTransport(newTransport func() transport.Transport) *Manager {
+	return &Manager{
+		devices:      map[string]*Uniden{},
+		newTransport: newTransport,
+		Verbose:      true,
+	}
+}
+
+func (mg *Manager) OnEvent(callback func(e ManagerEvent)) {
+	mg.onEvent = callback
+}
+
+// Add registers a detector. Adding an address twice returns the existing
+// instance.
+func (mg *Manager) Add(address string, model types.Model) (*Uniden, error) {
+	mg.mu.Lock()
+	defer mg.mu.Unlock()
+
+	if u, ok := mg.devices[address]; ok {
+		return u, nil
+	}
+
+	u, err := NewUnidenWithTransport(model, mg.newTransport())
+	if err != nil {
+		return nil, err
+	}
+
+	u.Verbose = mg.Verbose
+	u.address = address
+	mg.wire(u)
+
+	mg.devices[address] = u
+
+	if mg.server != nil {
+		mg.server.addDevice(u)
+	}
+
+	return u, nil
+}
+
+// Remove closes a detector and stops managing it. ctx bounds the wait for its
+// background goroutines, as in Close. The shared server keeps running.
+func (mg *Manager) Remove(ctx context.Context, address string) error {
+	mg.mu.Lock()
+	u, ok := mg.devices[address]
+	delete(mg.devices, address)
+	server := mg.server
+	mg.mu.Unlock()
+
+	if !ok {
+		return nil
+	}
+
+	if server != nil {
+		server.removeDevice(u)
+	}
+
+	return u.Close(ctx)
+}
+
+func (mg *Manager) Get(address string) *Uniden {
+	mg.mu.Lock()
+	defer mg.mu.Unlock()
+
+	return mg.devices[address]
+}
+
+func (mg *Manager) Devices() []*Uniden {
+	mg.mu.Lock()
+	defer mg.mu.Unlock()
+
+	devices := make([]*Uniden, 0, len(mg.devices))
+	for _, u := range mg.devices {
+		devices = append(devices, u)
+	}
+
+	return devices
+}
+
+// ConnectAll connects every managed detector concurrently and returns the
+// errors of the ones that failed, keyed by address.
+func (mg *Manager) ConnectAll() map[string]error {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	errs := map[string]error{}
+
+	for _, u := range mg.Devices() {
+		wg.Add(1)
+		go func(u *Uniden) {
+			defer wg.Done()
+
+			if err := u.Connect(u.Address()); err != nil {
+				mu.Lock()
+				errs[u.Address()] = fmt.Errorf("connect %s: %w", u.Address(), err)
+				mu.Unlock()
+			}
+		}(u)
+	}
+
+	wg.Wait()
+
+	return errs
+}
+
+func (mg *Manager) DisconnectAll() {
+	for _, u := range mg.Devices() {
+		u.Disconnect()
+	}
+}
+
+// StartServer serves every managed device, including ones added later, from a
+// single port.
+func (mg *Manager) StartServer(port int) (*UnidenInterfaceServer, error) {
+	mg.mu.Lock()
+	defer mg.mu.Unlock()
+
+	server := newServer(port)
+	for _, u := range mg.devices {
+		server.addDevice(u)
+	}
+
+	err := server.start()
+	if err != nil {
+		for _, u := range mg.devices {
+			server.removeDevice(u)
+		}
+		return nil, err
+	}
+
+	mg.server = server
+
+	return server, nil
+}
+
+// Close closes every managed device and the shared server.
+func (mg *Manager) Close(ctx context.Context) error {
+	mg.mu.Lock()
+	server := mg.server
+	mg.server = nil
+	mg.mu.Unlock()
+
+	var errs []error
+	for _, u := range mg.Devices() {
+		if err := u.Close(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("close %s: %w", u.Address(), err))
+		}
+	}
+
+	// Closing the last device normally shuts the server down already
+	if server != nil {
+		if err := server.shutdown(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (mg *Manager) wire(u *Uniden) {
+	u.OnRadarEvent(func(alerts []RadarEvent) {
+		mg.emit(ManagerEvent{Address: u.Address(), Kind: RadarEventKind, Alerts: alerts})
+	})
+
+	u.OnStatusUpdate(func(status Status) {
+		mg.emit(ManagerEvent{Address: u.Address(), Kind: StatusEventKind, Status: status})
+	})
+
+	u.OnSettingsChange(func(settings Settings) {
+		mg.emit(ManagerEvent{Address: u.Address(), Kind: SettingsEventKind, Settings: settings})
+	})
+
+	u.OnConnectionStateChange(func(from ConnectionState, to ConnectionState) {
+		mg.emit(ManagerEvent{Address: u.Address(), Kind: ConnectionEventKind, State: to})
+	})
+
+	u.OnSimulatedCommand(func(c SimulatedCommand) {
+		mg.emit(ManagerEvent{Address: u.Address(), Kind: SimulatedEventKind, Command: c})
+	})
+}
+
+func (mg *Manager) emit(e ManagerEvent) {
+	if mg.onEvent == nil {
+		return
+	}
+
+	e.Time = time.Now()
+	(mg.onEvent)(e)
+}
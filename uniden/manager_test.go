@@ -0,0 +1,47 @@
+package uniden
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/smoke7385/smk-uniden-bluetooth/transport"
+	"github.com/smoke7385/smk-uniden-bluetooth/types"
+)
+
+func TestManagerRemoveClosesDevice(t *testing.T) {
+	d := newFakeDevice(t, types.R4)
+
+	mg := NewManagerWithTransport(func() transport.Transport { return d.mem })
+	mg.Verbose = false
+
+	u, err := mg.Add(fakeAddress, types.R4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u.ReconnectPolicy.Enabled = false
+
+	if err := u.Connect(fakeAddress); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := mg.Remove(ctx, fakeAddress); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+
+	if mg.Get(fakeAddress) != nil {
+		t.Error("device still managed after Remove")
+	}
+
+	// Close cancels the lifetime and waits for the queue and dispatcher
+	if u.lifetime.Err() == nil {
+		t.Error("device lifetime not cancelled by Remove")
+	}
+
+	if state := u.State(); state != Idle {
+		t.Errorf("state after Remove = %s, want %s", state, Idle)
+	}
+}
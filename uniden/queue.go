@@ -0,0 +1,258 @@
+package uniden
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/smoke7385/smk-uniden-bluetooth/command"
+	"github.com/smoke7385/smk-uniden-bluetooth/protocol"
+)
+
+// QueueConfig controls the command queue. Commands are written one at a time,
+// at least Pacing apart. A SETC command is confirmed by the settings
+// notification holding the new value at its storage index, any other command
+// by its reply. A SETC attempt that is not confirmed within Timeout is retried
+// up to Retries times. Other commands are written once, their reply format is
+// unverified and a missing reply does not show the write was lost.
+type QueueConfig struct {
+	Pacing  time.Duration
+	Timeout time.Duration
+	Retries int
+}
+
+var DefaultQueueConfig = QueueConfig{
+	Pacing:  100 * time.Millisecond,
+	Timeout: 2 * time.Second,
+	Retries: 2,
+}
+
+type queuedCommand struct {
+	ctx    context.Context
+	cmd    command.Command
+	result chan queueResult
+
+	// Write even if the last settings image already holds the value
+	force bool
+}
+
+type queueResult struct {
+	response Response
+	err      error
+}
+
+// settingWaiter waits for the settings image to hold value at index.
+type settingWaiter struct {
+	index int
+	value int
+	done  chan struct{}
+}
+
+// enqueue hands a command to the queue and waits for its outcome.
+func (m *Uniden) enqueue(ctx context.Context, cmd command.Command, force bool) (Response, error) {
+	err := m.startQueue()
+	if err != nil {
+		return Response{}, err
+	}
+
+	queued := &queuedCommand{
+		ctx:    ctx,
+		cmd:    cmd,
+		result: make(chan queueResult, 1),
+		force:  force,
+	}
+
+	select {
+	case m.queue <- queued:
+	case <-ctx.Done():
+		return Response{}, fmt.Errorf("%w: %s: %w", ErrNoResponse, cmd, ctx.Err())
+	case <-m.lifetime.Done():
+		return Response{}, ErrClosed
+	}
+
+	select {
+	case result := <-queued.result:
+		return result.response, result.err
+	case <-m.lifetime.Done():
+		return Response{}, ErrClosed
+	}
+}
+
+// startQueue starts the queue worker on first use.
+func (m *Uniden) startQueue() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return ErrClosed
+	}
+
+	if m.queue == nil {
+		m.queue = make(chan *queuedCommand)
+		m.wg.Add(1)
+		go m.runQueue(m.queue)
+	}
+
+	return nil
+}
+
+func (m *Uniden) runQueue(queue chan *queuedCommand) {
+	defer m.wg.Done()
+
+	var last time.Time
+
+	for {
+		select {
+		case <-m.lifetime.Done():
+			return
+		case queued := <-queue:
+			if err := queued.ctx.Err(); err != nil {
+				queued.result <- queueResult{err: fmt.Errorf("%w: %s: %w", ErrNoResponse, queued.cmd, err)}
+				continue
+			}
+
+			if wait := m.Queue.Pacing - time.Since(last); wait > 0 {
+				select {
+				case <-time.After(wait):
+				case <-m.lifetime.Done():
+					return
+				}
+			}
+
+			response, err := m.execute(queued.ctx, queued.cmd, queued.force)
+			last = time.Now()
+
+			queued.result <- queueResult{response, err}
+		}
+	}
+}
+
+// execute runs a command until it is confirmed, rejected or out of retries.
+func (m *Uniden) execute(ctx context.Context, cmd command.Command, force bool) (Response, error) {
+	for attempt := 0; ; attempt++ {
+		response, err := m.attempt(ctx, cmd, force)
+		if err == nil || ctx.Err() != nil || attempt >= m.Queue.Retries {
+			return response, err
+		}
+
+		if !isTimeout(err) || cmd.Verb != command.VerbSetSetting {
+			return response, err
+		}
+
+		m.println("Retrying command", cmd.String(), "after", err.Error())
+	}
+}
+
+// attempt writes a command once and waits for its confirmation.
+func (m *Uniden) attempt(ctx context.Context, cmd command.Command, force bool) (Response, error) {
+	ctx, cancel := context.WithTimeout(ctx, m.Queue.Timeout)
+	defer cancel()
+
+	var confirmed chan struct{}
+	if cmd.Verb == command.VerbSetSetting {
+		waiter := m.waitForSetting(cmd.Index, cmd.Value, !force)
+		defer m.dropSettingWaiter(waiter)
+
+		// Nothing to write, the device already holds the value
+		select {
+		case <-waiter.done:
+			return Response{}, nil
+		default:
+		}
+
+		confirmed = waiter.done
+	}
+
+	pending := &pendingCommand{
+		verb:  string(cmd.Verb),
+		reply: make(chan Response, 1),
+	}
+
+	m.mu.Lock()
+	m.pendingCommands = append(m.pendingCommands, pending)
+	m.mu.Unlock()
+	defer m.dropPendingCommand(pending)
+
+	err := m.SendArbitraryCommand(cmd.String())
+	if err != nil {
+		return Response{}, err
+	}
+
+	var response Response
+	reply := pending.reply
+
+	for {
+		select {
+		case response = <-reply:
+			if response.Status == protocol.ResponseRejected {
+				return response, fmt.Errorf("%w: %s -> %s", ErrCommandRejected, cmd, response.Raw)
+			}
+
+			if confirmed == nil {
+				return response, nil
+			}
+
+			// Accepted, wait for the settings to reflect it
+			reply = nil
+		case <-confirmed:
+			return response, nil
+		case <-ctx.Done():
+			if confirmed != nil {
+				return response, fmt.Errorf("%w: %s: %w", ErrNotConfirmed, cmd, ctx.Err())
+			}
+			return response, fmt.Errorf("%w: %s: %w", ErrNoResponse, cmd, ctx.Err())
+		}
+	}
+}
+
+func isTimeout(err error) bool {
+	return errors.Is(err, ErrNoResponse) || errors.Is(err, ErrNotConfirmed)
+}
+
+// waitForSetting registers a waiter. With current set it is already done if
+// the last settings image holds the value, otherwise it waits for the next
+// image that does.
+func (m *Uniden) waitForSetting(index int, value int, current bool) *settingWaiter {
+	waiter := &settingWaiter{index: index, value: value, done: make(chan struct{})}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if v, ok := m.settingsImage.Value(index); current && ok && v == value {
+		close(waiter.done)
+		return waiter
+	}
+
+	m.settingWaiters = append(m.settingWaiters, waiter)
+	return waiter
+}
+
+func (m *Uniden) dropSettingWaiter(waiter *settingWaiter) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, w := range m.settingWaiters {
+		if w == waiter {
+			m.settingWaiters = append(m.settingWaiters[:i], m.settingWaiters[i+1:]...)
+			return
+		}
+	}
+}
+
+// resolveSettingWaiters releases every waiter whose value the image holds.
+// Called with m.mu held.
+func (m *Uniden) resolveSettingWaiters(image protocol.SettingsImage) {
+	waiting := m.settingWaiters[:0]
+
+	for _, w := range m.settingWaiters {
+		if v, ok := image.Value(w.index); ok && v == w.value {
+			close(w.done)
+			continue
+		}
+
+		waiting = append(waiting, w)
+	}
+
+	m.settingWaiters = waiting
+}
@@ -1,8 +1,11 @@
 package uniden
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
+	"net"
+	"sync"
 
 	"github.com/smoke7385/smk-uniden-bluetooth/utils"
 	socket "github.com/zishang520/socket.io/v2/socket"
@@ -13,32 +16,94 @@ import (
 
 // SERVER
 type UnidenInterfaceServer struct {
+	mu      sync.Mutex
 	clients []*socket.Socket
 	socket  *socket.Server
-	uniden  *Uniden
+	http    *http.Server
+	unidens []*Uniden
 	port    int
+	wg      sync.WaitGroup
 }
 
 // https://github.com/googollee/go-socket.io/tree/master/_examples
 func NewServer(uniden *Uniden, port int) *UnidenInterfaceServer {
+	return newServer(port, uniden)
+}
+
+func newServer(port int, unidens ...*Uniden) *UnidenInterfaceServer {
 	server := socket.NewServer(nil, nil)
 
 	uis := UnidenInterfaceServer{
 		socket: server,
-		uniden: uniden,
 		port:   port,
 	}
 
+	for _, uniden := range unidens {
+		uis.addDevice(uniden)
+	}
+
 	return &uis
 }
 
-func (s *UnidenInterfaceServer) handleSettingsUpdate(settings *Settings) {
+// addDevice serves another device from this server. Events for every device
+// carry its address as the last argument.
+func (s *UnidenInterfaceServer) addDevice(uniden *Uniden) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	uniden.server = s
+	s.unidens = append(s.unidens, uniden)
+}
+
+// removeDevice stops serving a device and reports how many are left.
+func (s *UnidenInterfaceServer) removeDevice(uniden *Uniden) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, u := range s.unidens {
+		if u == uniden {
+			s.unidens = append(s.unidens[:i], s.unidens[i+1:]...)
+			break
+		}
+	}
+
+	uniden.server = nil
+
+	return len(s.unidens)
+}
+
+func (s *UnidenInterfaceServer) devices() []*Uniden {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]*Uniden(nil), s.unidens...)
+}
+
+func (s *UnidenInterfaceServer) handleSettingsUpdate(uniden *Uniden, settings *Settings) {
 	fmt.Println("broadasting settings update")
-	s.broadcast("settingsUpdate", s.uniden.Settings.Serialize())
+	s.broadcast("settingsUpdate", uniden.Settings.Serialize(), uniden.Address())
+}
+
+func (s *UnidenInterfaceServer) handleRadarEvent(uniden *Uniden, alerts []RadarEvent) {
+	s.broadcast("radarEvent", utils.LooseMarshal(alerts), uniden.Address())
+}
+
+func (s *UnidenInterfaceServer) handleStatusUpdate(uniden *Uniden, status Status) {
+	s.broadcast("statusUpdate", utils.LooseMarshal(status), uniden.Address())
+}
+
+// handleSimulatedCommand shows a dry run command to clients as a change that
+// was not sent to the device.
+func (s *UnidenInterfaceServer) handleSimulatedCommand(uniden *Uniden, command SimulatedCommand) {
+	s.broadcast("simulatedCommand", utils.LooseMarshal(command), uniden.Address())
 }
 
 func (s *UnidenInterfaceServer) broadcast(ev string, args ...any) {
-	for _, client := range s.clients {
+	s.mu.Lock()
+	clients := append([]*socket.Socket(nil), s.clients...)
+	s.mu.Unlock()
+
+	for _, client := range clients {
 		client.Emit(ev, args...)
 	}
 }
@@ -46,9 +111,18 @@ func (s *UnidenInterfaceServer) broadcast(ev string, args ...any) {
 func (s *UnidenInterfaceServer) listenForSocketEvents() {
 	s.socket.On("connection", func(clients ...any) {
 		client := clients[0].(*socket.Socket)
+
+		s.mu.Lock()
 		s.clients = append(s.clients, client)
+		s.mu.Unlock()
+
+		for _, uniden := range s.devices() {
+			client.Emit("settingsUpdate", uniden.Settings.Serialize(), uniden.Address())
 
-		client.Emit("settingsUpdate", s.uniden.Settings.Serialize())
+			for _, command := range uniden.SimulatedCommands() {
+				client.Emit("simulatedCommand", utils.LooseMarshal(command), uniden.Address())
+			}
+		}
 
 		client.On("handshake", func(data ...any) {
 			fmt.Println("handshake", data)
@@ -58,14 +132,65 @@ func (s *UnidenInterfaceServer) listenForSocketEvents() {
 	})
 }
 
-func (s *UnidenInterfaceServer) start() {
-	http.Handle("/", s.socket.ServeHandler(nil))
+// start binds the port and serves in the background until shutdown is called.
+func (s *UnidenInterfaceServer) start() error {
+	mux := http.NewServeMux()
+	mux.Handle("/", s.socket.ServeHandler(nil))
 
 	// EVENT HANDLER
 	s.listenForSocketEvents()
 
 	portString := utils.ConcatenateStrings(":", strconv.Itoa(s.port))
-	s.uniden.println("Server started on port:", portString)
 
-	log.Fatal(http.ListenAndServe(portString, nil))
+	listener, err := net.Listen("tcp", portString)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.http = &http.Server{Handler: mux}
+	s.mu.Unlock()
+
+	s.println("Server started on port:", portString)
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		err := s.http.Serve(listener)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.println("Server stopped:", err)
+		}
+	}()
+
+	return nil
+}
+
+// shutdown closes every socket connection, stops the HTTP server and waits
+// for it to exit.
+func (s *UnidenInterfaceServer) shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	httpServer := s.http
+	s.http = nil
+	s.clients = nil
+	s.mu.Unlock()
+
+	if httpServer == nil {
+		return nil
+	}
+
+	s.socket.Close(nil)
+	err := httpServer.Shutdown(ctx)
+	s.wg.Wait()
+
+	return err
+}
+
+func (s *UnidenInterfaceServer) println(args ...interface{}) {
+	for _, uniden := range s.devices() {
+		if uniden.Verbose {
+			uniden.println(args...)
+			return
+		}
+	}
 }
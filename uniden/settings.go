@@ -1,17 +1,16 @@
 package uniden
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 
 	"fmt"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/smoke7385/smk-uniden-bluetooth/types"
 	"github.com/smoke7385/smk-uniden-bluetooth/utils"
-	"tinygo.org/x/bluetooth"
 )
 
 // Settings Generators
@@ -116,60 +115,6 @@ func getSpeedValues(s *Settings,
 	}
 }
 
-// TODO: Make less retarded - make name first param
-func generateBool(indexes ...int) func(name string) *Setting {
-	si := map[types.Model]int{}
-	setting := Setting{
-		Values: BooleanValues,
-	}
-
-	for i, v := range indexes {
-		if v == -1 {
-			continue
-		}
-		if i == 0 {
-			si["R4"] = v
-		}
-		if i == 1 {
-			si["R8"] = v
-		}
-		if i == 2 {
-			si["R9"] = v
-		}
-	}
-
-	setting.StorageIndex = si
-
-	return func(name string) *Setting {
-		setting.Name = name
-		return &setting
-	}
-}
-
-func generateKaSegment(segNum int, r4i int, r8i int, r9i int) *Setting {
-	return &Setting{
-		Name:   "Ka Segment " + strconv.Itoa(segNum),
-		Values: BooleanValues,
-		StorageIndex: map[types.Model]int{
-			types.R4: (r4i - 1) + segNum,
-			types.R8: (r8i - 1) + segNum,
-			types.R9: (r9i - 1) + segNum,
-		},
-	}
-}
-
-var adapter = bluetooth.DefaultAdapter
-
-type RadarEvent struct {
-	Band      types.Band
-	Frequency float32
-	Strength  int
-
-	LastUpdate time.Time
-
-	// TODO: Add the rest of the fields.
-}
-
 type Settings []*Setting
 
 var BooleanValues = Values{
@@ -226,6 +171,31 @@ func (s *Settings) Serialize() string {
 	return fmt.Sprintf("[%s]", strings.Join(serializedSettings, ","))
 }
 
+// forModel returns copies of the settings that have a storage index on
+// model. An AutoDetect model keeps every setting until the model is known.
+func (s *Settings) forModel(model types.Model) Settings {
+	var supported Settings
+	for _, setting := range *s {
+		if _, ok := setting.StorageIndex[model]; ok || model == types.AutoDetect {
+			supported = append(supported, setting)
+		}
+	}
+
+	return supported.clone()
+}
+
+// clone copies every setting so that each Uniden has its own state. Value
+// tables and storage indices are shared, they are never mutated.
+func (s *Settings) clone() Settings {
+	cloned := make(Settings, len(*s))
+	for i, setting := range *s {
+		copied := *setting
+		cloned[i] = &copied
+	}
+
+	return cloned
+}
+
 func (s *Settings) getByDeviceStorageIndex(index int) (*Setting, error) {
 	for _, setting := range *s {
 		// setting := &(s)[i]
@@ -237,6 +207,90 @@ func (s *Settings) getByDeviceStorageIndex(index int) (*Setting, error) {
 	return nil, errors.New("setting not found")
 }
 
+// getAllByDeviceStorageIndex returns every setting stored at index, more than
+// one where the definitions share an index.
+func (s *Settings) getAllByDeviceStorageIndex(index int) []*Setting {
+	var settings []*Setting
+	for _, setting := range *s {
+		if setting.getDeviceStorageIndex() == index {
+			settings = append(settings, setting)
+		}
+	}
+
+	return settings
+}
+
+// settingNames joins the names of settings for messages.
+func settingNames(settings []*Setting) string {
+	names := make([]string, len(settings))
+	for i, setting := range settings {
+		names[i] = setting.Name
+	}
+
+	return strings.Join(names, ", ")
+}
+
+var (
+	ErrUnknownStorageIndex   = errors.New("no setting at storage index")
+	ErrAmbiguousStorageIndex = errors.New("several settings share storage index")
+)
+
+// ValidateStorageValue checks that value is valid for the setting stored at
+// index, so Settings can validate commands. An index shared by several
+// settings cannot be validated and is rejected, write those by name.
+func (s *Settings) ValidateStorageValue(index int, value int) error {
+	matches := s.getAllByDeviceStorageIndex(index)
+
+	if len(matches) == 0 {
+		return fmt.Errorf("%w %d", ErrUnknownStorageIndex, index)
+	}
+
+	if len(matches) > 1 {
+		return fmt.Errorf("%w %d: %s", ErrAmbiguousStorageIndex, index, settingNames(matches))
+	}
+
+	setting := matches[0]
+	err := setting.ValidateValueInt(value)
+	if err != nil {
+		return fmt.Errorf("%s: %w", setting.Name, err)
+	}
+
+	return nil
+}
+
+var (
+	ErrSettingNotFound    = errors.New("setting not found")
+	ErrUnsupportedOnModel = errors.New("setting not supported on this model")
+)
+
+// lookup finds a setting by key or name, telling settings that do not exist
+// apart from settings the model lacks.
+func (s *Settings) lookup(name string, model types.Model) (*Setting, error) {
+	if setting := s.getByKey(name); setting != nil {
+		return setting, nil
+	}
+
+	if setting := s.getByName(name); setting != nil {
+		return setting, nil
+	}
+
+	if defs := definitions(); defs.getByKey(name) != nil || defs.getByName(name) != nil {
+		return nil, fmt.Errorf("%w: %s on %s", ErrUnsupportedOnModel, name, model)
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrSettingNotFound, name)
+}
+
+func (s *Settings) getByKey(key string) *Setting {
+	for _, setting := range *s {
+		if setting.Key != "" && setting.Key == key {
+			return setting
+		}
+	}
+
+	return nil
+}
+
 func (s *Settings) getByName(name string) *Setting {
 	for _, setting := range *s {
 		if strings.EqualFold(setting.Name, name) {
@@ -281,20 +335,108 @@ type Setting struct {
 	Settings     *Settings
 	DefaultValue any
 
+	// Metadata to render a settings screen from. Key is stable, unlike Name.
+	Key         string
+	Category    Category
+	Kind        Kind
+	Unit        string
+	Description string
+
 	Uniden *Uniden
 
 	DynamicValues func(s *Settings) *Values
+
+	// Limits of range and speed settings
+	rangeDef *RangeDefinition
+	speedDef *SpeedDefinition
+}
+
+type Category string
+
+const (
+	CategoryBands       Category = "Bands"
+	CategorySensitivity Category = "Sensitivity"
+	CategoryFilters     Category = "Filters"
+	CategoryAudio       Category = "Audio"
+	CategoryDisplay     Category = "Display"
+	CategoryGPS         Category = "GPS"
+	CategoryTime        Category = "Time"
+	CategoryGeneral     Category = "General"
+)
+
+var Categories = []Category{
+	CategoryBands,
+	CategorySensitivity,
+	CategoryFilters,
+	CategoryAudio,
+	CategoryDisplay,
+	CategoryGPS,
+	CategoryTime,
+	CategoryGeneral,
+}
+
+// Kind tells a UI which control a setting needs.
+type Kind string
+
+const (
+	KindBool  Kind = "bool"
+	KindEnum  Kind = "enum"
+	KindRange Kind = "range"
+	KindSpeed Kind = "speed"
+	KindTime  Kind = "time-of-day"
+)
+
+// Limits returns the minimum, maximum and step of a range or speed setting,
+// in the unit it is displayed in. Speeds follow the Speed Units setting, so
+// the unit is returned as well.
+func (s *Setting) Limits() (min int, max int, step int, unit string, ok bool) {
+	if r := s.rangeDef; r != nil {
+		return r.Min, r.Max, r.Step, s.Unit, true
+	}
+
+	if sp := s.speedDef; sp != nil {
+		if s.Settings == nil {
+			return sp.MPH.Min, sp.MPH.Max, sp.MPH.Step, "mph", true
+		}
+
+		if units := s.Settings.getByName("Speed Units"); units != nil && units.CurrentValue().Name == "KPH" {
+			return sp.KPH.Min, sp.KPH.Max, sp.KPH.Step, "kph", true
+		}
+		return sp.MPH.Min, sp.MPH.Max, sp.MPH.Step, "mph", true
+	}
+
+	return 0, 0, 0, s.Unit, false
 }
 
+var ErrDetachedSetting = errors.New("setting is not bound to a device")
+
+// Update blocks until the device holds the new value.
 func (s *Setting) Update(valueInt int) error {
+	if s.Uniden == nil {
+		return ErrDetachedSetting
+	}
+
+	return s.UpdateContext(s.Uniden.lifetime, valueInt)
+}
+
+func (s *Setting) UpdateContext(ctx context.Context, valueInt int) error {
+	if s.Uniden == nil {
+		return ErrDetachedSetting
+	}
+
 	// Validate the value
 	err := s.ValidateValueInt(valueInt)
 	if err != nil {
 		return err
 	}
 
-	// Update the value
-	err = s.Uniden.UpdateSetting(s.Name, valueInt)
+	// Update the value, by key where there is one since names are not unique
+	name := s.Name
+	if s.Key != "" {
+		name = s.Key
+	}
+
+	err = s.Uniden.UpdateSettingContext(ctx, name, valueInt)
 	if err != nil {
 		return err
 	}
@@ -304,7 +446,7 @@ func (s *Setting) Update(valueInt int) error {
 }
 
 func (s *Setting) ValidateValueInt(valueInt int) error {
-	for _, v := range s.Values {
+	for _, v := range *s.GetValues() {
 		if v.ID == valueInt {
 			return nil
 		}
@@ -344,24 +486,50 @@ func (s *Setting) CurrentValue() *Value {
 	return s.GetValues().getByInt(s.ValueInt)
 }
 
+// getDeviceStorageIndex returns -1 when the setting does not exist on the
+// model, which matches no byte of a settings image.
 func (s *Setting) getDeviceStorageIndex() int {
 	// println("Getting storage index for index:", s.Model, s.Name)
-	return s.StorageIndex[s.Model]
+	index, ok := s.StorageIndex[s.Model]
+	if !ok {
+		return -1
+	}
+
+	return index
 }
 
 type SerializedSetting struct {
-	Name   string `json:"name,omitempty"`
-	Value  string `json:"value,omitempty"`
-	Values string `json:"values,omitempty"`
+	Key         string `json:"key"`
+	Name        string `json:"name"`
+	Category    string `json:"category"`
+	Kind        string `json:"kind"`
+	Unit        string `json:"unit,omitempty"`
+	Description string `json:"description,omitempty"`
+	Min         *int   `json:"min,omitempty"`
+	Max         *int   `json:"max,omitempty"`
+	Step        *int   `json:"step,omitempty"`
+	Value       string `json:"value"`
+	Values      string `json:"values"`
 }
 
 func (s *Setting) Serialize() string {
-	str, err := json.Marshal(SerializedSetting{
-		Value:  strconv.Itoa(s.CurrentValue().ID),
-		Values: s.Values.Serialize(),
-		Name:   s.Name,
-	})
+	serialized := SerializedSetting{
+		Key:         s.Key,
+		Name:        s.Name,
+		Category:    string(s.Category),
+		Kind:        string(s.Kind),
+		Unit:        s.Unit,
+		Description: s.Description,
+		Value:       strconv.Itoa(s.CurrentValue().ID),
+		Values:      s.GetValues().Serialize(),
+	}
+
+	if min, max, step, unit, ok := s.Limits(); ok {
+		serialized.Min, serialized.Max, serialized.Step = &min, &max, &step
+		serialized.Unit = unit
+	}
 
+	str, err := json.Marshal(serialized)
 	if err != nil {
 		return "{}"
 	}
@@ -1,16 +1,19 @@
 package uniden
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"strconv"
-	"strings"
+	"io"
+	"sync"
 	"time"
 
+	"github.com/smoke7385/smk-uniden-bluetooth/capture"
+	"github.com/smoke7385/smk-uniden-bluetooth/command"
+	"github.com/smoke7385/smk-uniden-bluetooth/protocol"
+	"github.com/smoke7385/smk-uniden-bluetooth/transport"
 	"github.com/smoke7385/smk-uniden-bluetooth/types"
 	"github.com/smoke7385/smk-uniden-bluetooth/utils"
-
-	"tinygo.org/x/bluetooth"
 )
 
 type UnidenCache struct {
@@ -40,23 +43,82 @@ type ConditionalCallbackEvent struct {
 	Callback  func(u *Uniden) error
 	Timeout   time.Duration
 	Completed bool
+
+	timer *time.Timer
 }
 
 func (cce *ConditionalCallbackEvent) Unregister() {
 	cce.Condition = nil
 	cce.Callback = nil
+
+	if cce.timer != nil {
+		cce.timer.Stop()
+	}
 }
 
 type Uniden struct {
-	Model   types.Model `validate:"required"`
-	Verbose bool
+	Model      types.Model
+	DeviceInfo DeviceInfo
+	Verbose    bool
+
+	// Reconnect behaviour after the link drops
+	ReconnectPolicy ReconnectPolicy
+	Watchdog        WatchdogConfig
+
+	// Pacing and retries of queued commands
+	Queue QueueConfig
 
 	// Internal state
-	server   *UnidenInterfaceServer
-	services []*types.Service
-	device   *types.Device
-	cache    UnidenCache
-	address  string
+	server    *UnidenInterfaceServer
+	transport transport.Transport
+	cache     UnidenCache
+	address   string
+
+	// Model passed to NewUniden, AutoDetect if the caller left it to the device
+	requestedModel types.Model
+	decoder        protocol.Decoder
+
+	// Connection state
+	mu             sync.Mutex
+	state          ConnectionState
+	closing        bool
+	reconnecting   bool
+	connectOptions ConnectOptions
+	mtu            uint16
+	link           LinkStatus
+	watchdogDone   chan bool
+	subscribed     []types.CharType
+
+	// Closed once a connect abandoned by its context has returned and been
+	// torn down
+	abandonedConnect chan struct{}
+
+	// Commands waiting for a reply on the response characteristic
+	pendingCommands []*pendingCommand
+
+	// Last settings image received, kept byte-for-byte for backups
+	settingsImage protocol.SettingsImage
+
+	// Command queue, started on first use, and the SETC commands waiting for
+	// the settings image to confirm them
+	queue          chan *queuedCommand
+	settingWaiters []*settingWaiter
+
+	// User callbacks waiting to run, see dispatch
+	callbacks      []func()
+	callbacksReady chan struct{}
+
+	// Dry run, commands are recorded instead of sent and simulated holds the
+	// values they would have written, by storage index
+	dryRun       bool
+	simulated    map[int]int
+	simulatedLog []SimulatedCommand
+
+	// Lifetime, cancelled by Close. Background goroutines are tracked by wg.
+	lifetime       context.Context
+	cancelLifetime context.CancelFunc
+	closed         bool
+	wg             sync.WaitGroup
 
 	// State
 	Settings Settings
@@ -64,136 +126,337 @@ type Uniden struct {
 	Status   Status
 
 	// Callbacks
-	conditionalCallbacks []*ConditionalCallbackEvent
-	onServerClientEvent  func(message string)
-	onRadarEvent         func(s []RadarEvent)
-	onSettingsChange     func(s Settings)
-	onStatusUpdate       func(s Status)
-	onDisconnect         func()
-	onConnect            func()
-}
+	conditionalCallbacks    []*ConditionalCallbackEvent
+	onConnectionStateChange func(from ConnectionState, to ConnectionState)
+	onError                 func(err error)
+	onLinkEvent             func(e LinkEvent)
+	onResponse              func(r Response)
+	onServerClientEvent     func(message string)
+	onRadarEvent            func(s []RadarEvent)
+	onSettingsChange        func(s Settings)
+	onSimulatedCommand      func(c SimulatedCommand)
+	onStatusUpdate          func(s Status)
+	onDisconnect            func()
+	onConnect               func()
+}
+
+// NewUniden creates a Uniden for the given model. Pass types.AutoDetect to
+// select the model from the device information service on connect. Models
+// without a registered decoder are rejected.
+func NewUniden(model types.Model) (*Uniden, error) {
+	return NewUnidenWithTransport(model, transport.NewDefaultBluetooth())
+}
+
+// NewUnidenWithTransport creates a Uniden that talks to the device through t
+// instead of the default Bluetooth adapter.
+func NewUnidenWithTransport(model types.Model, t transport.Transport) (*Uniden, error) {
+	var decoder protocol.Decoder
+	if model != types.AutoDetect {
+		var err error
+		decoder, err = protocol.Lookup(model, "")
+		if err != nil {
+			return nil, err
+		}
+	}
 
-func NewUniden(model types.Model) *Uniden {
-	var uniden = Uniden{Model: model, Verbose: true, Settings: defSettings}
-	for i := range uniden.Settings {
-		uniden.Settings[i].Settings = &uniden.Settings
-		uniden.Settings[i].Uniden = &uniden
-		uniden.Settings[i].Model = model
+	var uniden = Uniden{
+		Verbose:         true,
+		ReconnectPolicy: DefaultReconnectPolicy,
+		Watchdog:        DefaultWatchdogConfig,
+		Queue:           DefaultQueueConfig,
+		transport:       t,
+		requestedModel:  model,
+		decoder:         decoder,
+		link:            LinkStatus{LastNotification: map[types.CharType]time.Time{}},
 	}
+	uniden.lifetime, uniden.cancelLifetime = context.WithCancel(context.Background())
+	uniden.setModel(model)
 
 	uniden.cache = NewUnidenCache(&uniden)
 
-	return &uniden
+	return &uniden, nil
 }
 
+// StayOpen blocks until Close is called.
 func (m *Uniden) StayOpen() {
-	select {}
+	<-m.lifetime.Done()
 }
 
 func (m *Uniden) Connect(address string) error {
+	return m.ConnectContext(context.Background(), address, DefaultConnectOptions)
+}
+
+// ConnectContext connects to the device at address, giving up when ctx is
+// done. Whatever part of the connection was established is torn down again
+// on failure, so it is safe to retry.
+func (m *Uniden) ConnectContext(ctx context.Context, address string, opts ConnectOptions) error {
 	m.println("Connecting to device:", address, "...")
+	opts = opts.withDefaults()
 
 	// Enable bluetooth interface
-	utils.Must("enable BLE stack", adapter.Enable())
+	err := runContext(ctx, m.transport.Enable)
+	if err != nil {
+		return fmt.Errorf("enable BLE stack: %w", err)
+	}
+
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return ErrClosed
+	}
+	m.closing = false
+	m.connectOptions = opts
+	m.mu.Unlock()
+
+	// Reconnect whenever the link drops
+	m.transport.SetDisconnectHandler(m.handleLinkLost)
+
+	err = m.establish(ctx, address, opts)
+	if err != nil {
+		m.transport.Disconnect()
+		m.setState(Idle)
+		return err
+	}
+
+	return nil
+}
 
+// establish runs a single connection attempt: scan, connect, discover,
+// subscribe and sync the device state. It is used for the initial connection
+// and for every reconnect.
+func (m *Uniden) establish(ctx context.Context, address string, opts ConnectOptions) error {
 	// Scan for devices
-	result, err := m.scanForDevice(address)
+	m.setState(Scanning)
+	_, err := m.scanForDevice(ctx, address, opts.ScanTimeout)
 	if err != nil {
 		return err
 	}
 
 	// Connect to the found device
-	_device, err := adapter.Connect(result.Address, bluetooth.ConnectionParams{})
-	device := types.Device{Device: _device}
-	utils.Must("connect to device device", err)
+	m.setState(Connecting)
+	err = m.connectContext(ctx, address)
 	if err != nil {
-		return err
+		return fmt.Errorf("connect to %s: %w", address, err)
 	}
 
 	// Discover services
-	srvcs, err := device.DiscoverServices([]bluetooth.UUID{})
-	utils.Must("discover services", err)
-	if len(srvcs) == 0 {
-		return errors.New("no services identified")
+	m.setState(Discovering)
+	var chars []types.CharType
+	err = runContext(ctx, func() (err error) {
+		chars, err = m.transport.Discover()
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("discover services: %w", err)
+	}
+	if len(chars) == 0 {
+		return ErrNoServices
+	}
+
+	for _, required := range opts.RequiredCharacteristics {
+		if !utils.ValueInArray(required, chars) {
+			return fmt.Errorf("%w: %s", ErrCharacteristicMissing, required)
+		}
 	}
 
-	// Iterate over discovered services and characteristics
-	for _, _service := range srvcs {
-		service := types.Service{DeviceService: _service}
-		device.AddService(&service)
+	// Identify the device, and with it the decoder, before any frame arrives
+	err = m.readDeviceInfo()
+	if err != nil {
+		return err
+	}
 
-		characteristics, err := service.DiscoverCharacteristics([]bluetooth.UUID{})
-		if err != nil {
-			println(err)
+	// Subscribe to every characteristic that supports notifications, through
+	// a framer that reassembles fragmented notifications
+	m.mtu = DefaultATTMTU
+	m.subscribed = nil
+	for _, uuid := range chars {
+		uuid := uuid
+		mtu, err := m.transport.MTU(uuid)
+		if err == nil && mtu > m.mtu {
+			m.mtu = mtu
 		}
 
-		for _, _char := range characteristics {
-			characteristic := types.Characteristic{DeviceCharacteristic: _char}
-			// println("found characteristic for device ", service.UUID().String(), ": ", characteristic.UUID().String())
-			service.AddCharacteristic(&characteristic)
-			characteristic.AddCallback(m.handleCharacteristicUpdate)
+		framer := newFrameAssembler(uuid, mtu, func(frame []byte) {
+			if recorder, ok := m.transport.(frameRecorder); ok {
+				recorder.RecordFrame(uuid, frame)
+			}
+			m.handleCharacteristicUpdate(frame, uuid)
+		}, m.handleError)
+
+		err = m.transport.Subscribe(uuid, func(buf []byte) {
+			m.markNotification(uuid)
+			framer.push(buf)
+		})
+		if err != nil {
+			m.println("Could not subscribe to characteristic:", uuid.String(), err)
+			continue
 		}
 
-		m.services = append(m.services, &service)
+		m.subscribed = append(m.subscribed, uuid)
+	}
+
+	m.address = address
+
+	m.setState(Syncing)
+	if err := ctx.Err(); err != nil {
+		return err
 	}
 
 	// Request initial settings data
-	sErr := m.requestDeviceState()
+	sErr := m.requestDeviceState(ctx)
 	if sErr != nil {
 		println("Error getting device state:", sErr)
 	} else {
 		println("Device state synced successfully")
 	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
 	// Syncronize the time
-	tErr := m.SyncTime()
+	tErr := m.SyncTimeContext(ctx)
 	if tErr != nil {
 		println("Error syncing time:", tErr)
 	} else {
 		println("Device time synced successfully")
 	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
-	m.address = address
-	m.device = &device
+	m.setState(Ready)
+	m.startWatchdog()
+
+	if m.onConnect != nil {
+		(m.onConnect)()
+	}
 
 	return nil
 }
 
 // scanForDevice scans for the specified device address and returns the result.
-func (m *Uniden) scanForDevice(address string) (bluetooth.ScanResult, error) {
+func (m *Uniden) scanForDevice(ctx context.Context, address string, timeout time.Duration) (transport.ScanResult, error) {
 	m.println("Scanning for devices...")
-	ch := make(chan bluetooth.ScanResult, 1)
+	ch := make(chan transport.ScanResult, 1)
+	done := make(chan error, 1)
 
 	// Start scanning
-	err := adapter.Scan(func(adapter *bluetooth.Adapter, result bluetooth.ScanResult) {
-		if result.Address.String() == address {
-			m.println("Found Uniden device:", result.Address.String(), result.RSSI, result.LocalName())
-			adapter.StopScan()
-			ch <- result
-			return
-		}
-	})
-	if err != nil {
-		return bluetooth.ScanResult{}, err
-	}
+	go func() {
+		done <- m.transport.Scan(func(result transport.ScanResult) {
+			if result.Address == address {
+				m.println("Found Uniden device:", result.Address, result.RSSI, result.LocalName)
+				m.transport.StopScan()
+				select {
+				case ch <- result:
+				default:
+				}
+			}
+		})
+	}()
 
 	// Wait for the scan result
 	select {
 	case result := <-ch:
 		return result, nil
-	case <-time.After(10 * time.Second): // Timeout after 10 seconds
-		return bluetooth.ScanResult{}, errors.New("scan timeout")
+	case err := <-done:
+		if err == nil {
+			err = transport.ErrDeviceNotFound
+		}
+		return transport.ScanResult{}, fmt.Errorf("scan for %s: %w", address, err)
+	case <-time.After(timeout):
+		m.transport.StopScan()
+		return transport.ScanResult{}, fmt.Errorf("%w: %s not seen within %s", ErrScanTimeout, address, timeout)
+	case <-ctx.Done():
+		m.transport.StopScan()
+		return transport.ScanResult{}, ctx.Err()
 	}
 }
 
+// MTU returns the largest ATT MTU reported for the current connection.
+func (m *Uniden) MTU() uint16 {
+	return m.mtu
+}
+
+// Address returns the address of the device this instance connects to.
+func (m *Uniden) Address() string {
+	return m.address
+}
+
 func (m *Uniden) Disconnect() {
-	m.device.Disconnect()
+	m.mu.Lock()
+	m.closing = true
+	m.mu.Unlock()
+
+	m.stopWatchdog()
+	m.transport.Disconnect()
+	m.setState(Idle)
 
 	if m.onDisconnect != nil {
 		(m.onDisconnect)()
 	}
 }
 
+// Capture logs every notification, write and read to w as JSON lines, see
+// the capture package. It must be called before Connect.
+func (m *Uniden) Capture(w io.Writer) {
+	m.transport = capture.NewTransport(m.transport, w)
+}
+
+// Close shuts the instance down for good: scanning stops, notifications are
+// disabled, the device is disconnected, the server is shut down (unless other
+// devices still use it) and pending timers are cancelled. It waits for the
+// background goroutines to exit or for ctx to be done, whichever is first.
+func (m *Uniden) Close(ctx context.Context) error {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return nil
+	}
+	m.closed = true
+	m.closing = true
+	subscribed := m.subscribed
+	m.subscribed = nil
+	m.mu.Unlock()
+
+	m.cancelLifetime()
+
+	m.transport.StopScan()
+	m.stopWatchdog()
+	m.cancelConditionalCallbacks()
+
+	// Disable notifications and drop the link
+	for _, uuid := range subscribed {
+		m.transport.Unsubscribe(uuid)
+	}
+	m.transport.Disconnect()
+	m.transport.Release()
+	m.setState(Idle)
+
+	var err error
+	if server := m.server; server != nil {
+		if server.removeDevice(m) == 0 {
+			err = server.shutdown(ctx)
+		}
+	}
+
+	// Wait for background goroutines
+	done := make(chan struct{})
+	go func() {
+		m.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		if err == nil {
+			err = ctx.Err()
+		}
+	}
+
+	return err
+}
+
 // Callbacks / Listeners
 func (m *Uniden) OnRadarEvent(callback func(s []RadarEvent)) {
 	m.onRadarEvent = callback
@@ -215,6 +478,12 @@ func (m *Uniden) OnDisconnect(callback func()) {
 	m.onDisconnect = callback
 }
 
+// OnError is invoked for errors that happen outside of a call, such as a
+// malformed notification from the device.
+func (m *Uniden) OnError(callback func(err error)) {
+	m.onError = callback
+}
+
 func (m *Uniden) OnServerClientEvent(callback func(message string)) {
 	m.onServerClientEvent = callback
 }
@@ -223,10 +492,13 @@ func (m *Uniden) OnServerClientEvent(callback func(message string)) {
 func (m *Uniden) StartServer(port int) (*UnidenInterfaceServer, error) {
 	m.println("Starting server...")
 	server := NewServer(m, port)
-	m.server = server
 
 	// Start the server
-	go m.server.start()
+	err := server.start()
+	if err != nil {
+		server.removeDevice(m)
+		return nil, err
+	}
 
 	return server, nil
 }
@@ -238,20 +510,39 @@ func (m *Uniden) GetSettings() Settings {
 	return m.Settings
 }
 
+// RequestSettings reads the settings characteristic and applies the image as
+// if it had been notified.
+func (m *Uniden) RequestSettings(ctx context.Context) error {
+	return m.requestDeviceState(ctx)
+}
+
+// UpdateSetting writes a setting and blocks until the device holds the new
+// value. Callbacks run off the notification goroutine, so it may be called
+// from OnSettingsChange or a conditional callback.
 func (m *Uniden) UpdateSetting(setting string, valueInt int) error {
-	settingObj := m.Settings.getByName(setting)
+	return m.UpdateSettingContext(m.lifetime, setting, valueInt)
+}
 
-	if settingObj == nil {
-		return fmt.Errorf("Settings [%s] not found", setting)
+// UpdateSettingContext is UpdateSetting with a deadline for the whole write,
+// retries included.
+func (m *Uniden) UpdateSettingContext(ctx context.Context, setting string, valueInt int) error {
+	settingObj, err := m.Settings.lookup(setting, m.Model)
+	if err != nil {
+		return err
 	}
 
-	command := utils.ConcatenateStrings("BTreqSETC:", strconv.Itoa(settingObj.getDeviceStorageIndex()), "=", strconv.Itoa(valueInt))
+	// Validated by name, the storage index may be shared with other settings
+	err = settingObj.ValidateValueInt(valueInt)
+	if err != nil {
+		return fmt.Errorf("%s: %w", settingObj.Name, err)
+	}
 
-	// Write the command
-	// m.println("Sending command to device: ", command)
-	m.SendArbitraryCommand(command)
+	cmd := command.SetSetting(settingObj.getDeviceStorageIndex(), valueInt)
 
-	return nil
+	// Queue the command and wait for the device to hold the value
+	// m.println("Sending command to device: ", cmd)
+	_, err = m.send(ctx, cmd)
+	return err
 }
 
 // utils
@@ -307,6 +598,11 @@ func (m *Uniden) Unmute() error {
 }
 
 func (m *Uniden) SyncTime() error {
+	return m.SyncTimeContext(m.lifetime)
+}
+
+// SyncTimeContext is SyncTime, giving up when ctx is done.
+func (m *Uniden) SyncTimeContext(ctx context.Context) error {
 	timeStr := utils.GetDeviceTimeZoneGMT()
 	tSetting := m.Settings.getByName("Time zone")
 
@@ -319,10 +615,13 @@ func (m *Uniden) SyncTime() error {
 		return err
 	}
 
-	err = tSetting.Update(timeInt)
+	err = tSetting.UpdateContext(ctx, timeInt)
 	if err != nil {
 		println("Error syncing time:", err)
 	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
 
 	if !m.cache.TimeSynced {
 		m.cache.TimeSynced = true
@@ -331,22 +630,18 @@ func (m *Uniden) SyncTime() error {
 	return nil
 }
 
-func (m *Uniden) requestDeviceState() error {
-	// Find the command characteristic
-	char, err := m.getChar(types.C.Settings.String())
-	if err != nil {
-		println("Error finding settings characteristic to device")
-	}
-
+func (m *Uniden) requestDeviceState(ctx context.Context) error {
 	// Get value of the settings characteristic
 	var data []byte
-	_, err = char.Read(data)
-
+	err := runContext(ctx, func() (err error) {
+		data, err = m.transport.Read(types.C.Settings)
+		return err
+	})
 	if err != nil {
 		return err
 	}
 
-	m.handleSettingsUpdate(data, char)
+	m.handleSettingsUpdate(data, types.C.Settings)
 
 	return nil
 }
@@ -371,13 +666,20 @@ func (m *Uniden) RegisterConditionalCallback(
 	// Add the callback event to the list
 	m.conditionalCallbacks = append(m.conditionalCallbacks, &cce)
 
-	// Start the callback event
-	go func() {
-		time.Sleep(timeout)
+	// Drop the callback event once it times out
+	cce.timer = time.AfterFunc(timeout, func() {
 		if !cce.Completed {
 			cce.Unregister()
 		}
-	}()
+	})
+}
+
+func (m *Uniden) cancelConditionalCallbacks() {
+	for _, cce := range m.conditionalCallbacks {
+		cce.Unregister()
+	}
+
+	m.conditionalCallbacks = nil
 }
 
 func (m *Uniden) runCallbacks() {
@@ -386,6 +688,11 @@ func (m *Uniden) runCallbacks() {
 	}
 
 	for _, cce := range m.conditionalCallbacks {
+		// Completed or timed out
+		if cce.Condition == nil {
+			continue
+		}
+
 		if cce.Condition(m) {
 			cce.Callback(m)
 			cce.Completed = true
@@ -400,85 +707,119 @@ func (m *Uniden) runCallbacks() {
 func (m *Uniden) mark() {}
 
 // Internal event handlers
-func (m *Uniden) handleGenericAttribute(buf []byte, c *types.Characteristic) {}
+func (m *Uniden) handleGenericAttribute(buf []byte, uuid types.CharType) {}
+
+func (m *Uniden) handleSettingsUpdate(buf []byte, uuid types.CharType) {
+	image, err := m.decoder.DecodeSettings(buf)
+	if err != nil {
+		m.handleError(err)
+		return
+	}
 
-func (m *Uniden) handleSettingsUpdate(buf []byte, c *types.Characteristic) {
-	changed := false
+	m.mu.Lock()
+	m.settingsImage = image
+	simulated := m.simulatedValues()
+	m.mu.Unlock()
 
-	var changedSettings Settings
+	changedSettings := m.storeSettings(image, simulated)
+
+	if !m.cache.RecievedFirstSettings {
+		m.cache.RecievedFirstSettings = true
+	}
 
-	for index, value := range buf {
+	// Release queued writes now that Settings holds their values
+	m.mu.Lock()
+	m.resolveSettingWaiters(image)
+	m.mu.Unlock()
 
+	m.settingsChanged(changedSettings)
+}
+
+// storeSettings writes image into Settings, with overrides taking the place
+// of the image bytes at their storage index, and returns what changed.
+func (m *Uniden) storeSettings(image protocol.SettingsImage, overrides map[int]int) Settings {
+	var changedSettings Settings
+
+	store := func(index int, value int) {
 		setting, err := m.Settings.getByDeviceStorageIndex(index)
 		if err != nil {
-			continue
+			return
 		}
 
-		if setting.ValueInt != int(value) {
+		if setting.ValueInt != value {
 			changedSettings = append(changedSettings, setting)
-			setting.ValueInt = int(value)
-			changed = true
+			setting.ValueInt = value
 		}
 	}
 
-	if !m.cache.RecievedFirstSettings {
-		m.cache.RecievedFirstSettings = true
-	}
+	for index, value := range image {
+		if override, ok := overrides[index]; ok {
+			store(index, override)
+			continue
+		}
 
-	if m.onSettingsChange != nil && changed {
-		m.runCallbacks()
+		store(index, int(value))
+	}
 
-		if m.server != nil {
-			m.server.handleSettingsUpdate(&changedSettings)
+	for index, value := range overrides {
+		if index >= len(image) {
+			store(index, value)
 		}
-
-		// Invoke the onSettingsChange callback
-		(m.onSettingsChange)(m.Settings)
 	}
+
+	return changedSettings
 }
 
-func (m *Uniden) handleStatusUpdate(buf []byte, c *types.Characteristic) {
-	bStr := string(buf)
-	sections := strings.Split(bStr, "&")
+func (m *Uniden) settingsChanged(changedSettings Settings) {
+	if m.onSettingsChange != nil && len(changedSettings) > 0 {
+		m.dispatch(func() {
+			m.runCallbacks()
 
-	m.Status = Status{
-		Voltage: utils.ParseFloat32(sections[0]),
-		// What is sections[1]?
-		GPS: parseGPS(sections[2]),
-		// What is sections[3]?
-		Signal: utils.ParseFloat32(sections[4]),
-	}
+			if m.server != nil {
+				m.server.handleSettingsUpdate(m, &changedSettings)
+			}
 
-	if m.onStatusUpdate != nil {
-		(m.onStatusUpdate)(m.Status)
+			// Invoke the onSettingsChange callback
+			(m.onSettingsChange)(m.Settings)
+		})
 	}
 }
 
-func (m *Uniden) handleRadarEvent(buf []byte, c *types.Characteristic) {
-	bStr := string(buf)
-	signalSections := strings.Split(bStr, "&")
-	var alerts []RadarEvent = m.Alerts
+func (m *Uniden) handleStatusUpdate(buf []byte, uuid types.CharType) {
+	status, err := m.decoder.DecodeStatus(buf)
+	if err != nil {
+		m.handleError(err)
+		return
+	}
 
-	// TODO: Determine if the position of the signals matters.
-	for index, value := range signalSections {
-		// This is an empty signal. I suspect the Uniden can only hold 4 signals at a time.
-		if value == "0" {
-			if len(alerts) > index {
-				alerts[index] = RadarEvent{}
-			}
-			continue
+	m.Status = status
+
+	m.dispatch(func() {
+		if m.server != nil {
+			m.server.handleStatusUpdate(m, status)
 		}
 
-		sections := strings.Split(value, ",")
-		// 1,00,K,5,123,24.1090,0,1
-		// ?, ?, band, strength, distance???, frequency, ?, ?
+		if m.onStatusUpdate != nil {
+			(m.onStatusUpdate)(status)
+		}
+	})
+}
 
-		event := RadarEvent{
-			Frequency: utils.ParseFloat32(sections[5]),
-			Strength:  utils.ParseInt(sections[3]),
-			Band:      types.Band(sections[2]),
+func (m *Uniden) handleRadarEvent(buf []byte, uuid types.CharType) {
+	events, err := m.decoder.DecodeRadar(buf)
+	if err != nil {
+		m.handleError(err)
+		return
+	}
 
-			LastUpdate: time.Now(),
+	var alerts []RadarEvent = m.Alerts
+
+	// Alerts keep the position of their slot, empty slots hold an event with
+	// only Slot set.
+	// I suspect the Uniden can only hold 4 signals at a time.
+	for index, event := range events {
+		if event.Band != "" {
+			event.LastUpdate = time.Now()
 		}
 
 		if len(alerts) > index {
@@ -486,17 +827,48 @@ func (m *Uniden) handleRadarEvent(buf []byte, c *types.Characteristic) {
 		} else {
 			alerts = append(alerts, event)
 		}
-
 	}
 
 	m.Alerts = alerts
 
-	if m.onRadarEvent != nil {
-		(m.onRadarEvent)(alerts)
+	// Later frames update m.Alerts in place
+	alerts = append([]RadarEvent(nil), alerts...)
+
+	m.dispatch(func() {
+		if m.server != nil {
+			m.server.handleRadarEvent(m, alerts)
+		}
+
+		if m.onRadarEvent != nil {
+			(m.onRadarEvent)(alerts)
+		}
+	})
+}
+
+func (m *Uniden) handleResponse(buf []byte, uuid types.CharType) {
+	// The reply format is unverified, so replies outside it are passed on
+	// raw instead of being reported as errors
+	response, err := protocol.ParseResponse(buf)
+	if err != nil {
+		m.println("Unparsed response:", response.Raw)
+	} else if !m.resolveCommand(response) {
+		m.println("Unsolicited response:", response.Raw)
+	}
+
+	if m.onResponse != nil {
+		m.dispatch(func() {
+			(m.onResponse)(response)
+		})
 	}
 }
 
-func (m *Uniden) handleResponse(buf []byte, c *types.Characteristic) {}
+func (m *Uniden) handleError(err error) {
+	m.println("Error:", err)
+
+	if m.onError != nil {
+		(m.onError)(err)
+	}
+}
 
 func (m *Uniden) handleServerClientEvent(message []byte) {
 	if m.onServerClientEvent != nil {
@@ -504,46 +876,40 @@ func (m *Uniden) handleServerClientEvent(message []byte) {
 	}
 }
 
-func (m *Uniden) handleCharacteristicUpdate(buf []byte, c *types.Characteristic) {
-	// m.println("Got data from char: ", types.CharType(c.UUID().String()))
-	switch types.CharType(c.UUID().String()) {
+func (m *Uniden) handleCharacteristicUpdate(buf []byte, uuid types.CharType) {
+	// m.println("Got data from char: ", uuid)
+	switch uuid {
 	case types.C.GenericAttribute:
-		m.handleGenericAttribute(buf, c)
+		m.handleGenericAttribute(buf, uuid)
 	case types.C.Settings:
-		m.handleSettingsUpdate(buf, c)
+		m.handleSettingsUpdate(buf, uuid)
 	case types.C.Status:
-		m.handleStatusUpdate(buf, c)
+		m.handleStatusUpdate(buf, uuid)
 	case types.C.RadarEvent:
-		m.handleRadarEvent(buf, c)
+		m.handleRadarEvent(buf, uuid)
 	case types.C.Response:
-		m.handleResponse(buf, c)
+		m.handleResponse(buf, uuid)
 	default:
-		m.println("Recieved data from unhandled characteristic:", c.UUID().String())
+		m.println("Recieved data from unhandled characteristic:", uuid.String())
 	}
 }
 
-func (m *Uniden) getChar(characteristic string) (*types.Characteristic, error) {
-	for _, s := range m.services {
-		for _, c := range s.Characteristics {
-			if c.UUID().String() == characteristic {
-				return &c, nil
-			}
-		}
+// SendArbitraryCommand writes a raw command immediately, bypassing the queue
+// and validation.
+func (m *Uniden) SendArbitraryCommand(command string) error {
+	if m.DryRun() {
+		m.simulate(command)
+		return nil
 	}
 
-	return nil, errors.New("characteristic not found")
-}
-
-func (m *Uniden) SendArbitraryCommand(command string) {
-	// Find the command characteristic
-	char, err := m.getChar(types.C.Command.String())
+	// Write the command
+	// m.println("Sending command to device:", command)
+	err := m.transport.Write(types.C.Command, []byte(command))
 	if err != nil {
-		println("Error writing to device")
+		m.println("Error writing to device:", err)
 	}
 
-	// Write the command
-	// m.println("Sending command to device:", command)
-	char.WriteWithoutResponse([]byte(command))
+	return err
 }
 
 func (m *Uniden) println(args ...interface{}) {
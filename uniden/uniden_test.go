@@ -0,0 +1,130 @@
+package uniden
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/smoke7385/smk-uniden-bluetooth/command"
+	"github.com/smoke7385/smk-uniden-bluetooth/protocol"
+	"github.com/smoke7385/smk-uniden-bluetooth/types"
+)
+
+// receive waits for the next value from a callback.
+func receive[T any](t *testing.T, ch <-chan T) T {
+	t.Helper()
+
+	select {
+	case v := <-ch:
+		return v
+	case <-time.After(2 * time.Second):
+		t.Fatal("callback not invoked")
+	}
+
+	var zero T
+	return zero
+}
+
+func TestUnidenOverMemoryTransport(t *testing.T) {
+	d := newFakeDevice(t, types.R4)
+
+	xBandValues := make(chan int, 4)
+	statusUpdates := make(chan Status, 4)
+	radarEvents := make(chan []RadarEvent, 4)
+
+	u := d.connect(t, types.R4, func(u *Uniden) {
+		u.OnSettingsChange(func(s Settings) { xBandValues <- s.getByName("X Band").ValueInt })
+		u.OnStatusUpdate(func(s Status) { statusUpdates <- s })
+		u.OnRadarEvent(func(e []RadarEvent) { radarEvents <- e })
+	})
+
+	if u.State() != Ready {
+		t.Fatalf("state %s after connecting, want %s", u.State(), Ready)
+	}
+
+	// Settings notification
+	xBand := u.Settings.getByName("X Band")
+	xBandValue := otherValue(xBand)
+
+	image, err := u.SettingsImage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	image[xBand.getDeviceStorageIndex()] = byte(xBandValue)
+	d.notify(types.C.Settings, image)
+
+	// Syncing the time while connecting may have changed settings before
+	for receive(t, xBandValues) != xBandValue {
+	}
+
+	// Status notification. The layout is the one the parser expects, no
+	// frame has been captured from a device.
+	d.notify(types.C.Status, []byte("13.8&0&270,55,120,C&0&4.5"))
+
+	status := receive(t, statusUpdates)
+	if status.Voltage != 13.8 || status.Signal != 4.5 || status.GPS.State != protocol.GPSConnected {
+		t.Errorf("status %+v", status)
+	}
+
+	// Radar notification, a captured alert followed by an empty slot
+	d.notify(types.C.RadarEvent, []byte("1,00,K,5,123,24.1090,0,1&0"))
+
+	alerts := receive(t, radarEvents)
+	if len(alerts) != 2 {
+		t.Fatalf("%d alerts, want 2", len(alerts))
+	}
+	if alerts[0].Band != types.K || alerts[0].Strength != 5 || alerts[0].Frequency != 24.109 {
+		t.Errorf("alert %+v", alerts[0])
+	}
+	if alerts[1].Slot != 1 || alerts[1].Band != "" {
+		t.Errorf("empty slot %+v", alerts[1])
+	}
+
+	// SETC write, confirmed by the settings image the device sends back
+	kBand := u.Settings.getByName("K Band")
+	kBandValue := otherValue(kBand)
+
+	if err := u.UpdateSetting("K Band", kBandValue); err != nil {
+		t.Fatalf("UpdateSetting: %v", err)
+	}
+
+	want := command.SetSetting(kBand.getDeviceStorageIndex(), kBandValue).Encode()
+
+	var written bool
+	for _, write := range d.mem.Writes() {
+		if write.UUID == types.C.Command && bytes.Equal(write.Data, want) {
+			written = true
+		}
+	}
+	if !written {
+		t.Errorf("no %s written to the command characteristic", want)
+	}
+
+	if kBand.ValueInt != kBandValue || d.value(kBand.getDeviceStorageIndex()) != kBandValue {
+		t.Errorf("K Band = %d, device holds %d, want %d", kBand.ValueInt, d.value(kBand.getDeviceStorageIndex()), kBandValue)
+	}
+}
+
+func TestConnectWithZeroOptions(t *testing.T) {
+	d := newFakeDevice(t, types.R4)
+
+	u, err := NewUnidenWithTransport(types.R4, d.mem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u.Verbose = false
+	u.ReconnectPolicy.Enabled = false
+	t.Cleanup(func() { u.Close(context.Background()) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := u.ConnectContext(ctx, fakeAddress, ConnectOptions{}); err != nil {
+		t.Fatalf("ConnectContext with zero options: %v", err)
+	}
+
+	if u.connectOptions.ScanTimeout != DefaultConnectOptions.ScanTimeout {
+		t.Errorf("scan timeout %v, want %v", u.connectOptions.ScanTimeout, DefaultConnectOptions.ScanTimeout)
+	}
+}
@@ -0,0 +1,269 @@
+package uniden
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/smoke7385/smk-uniden-bluetooth/types"
+)
+
+type ProblemKind string
+
+const (
+	// Two settings share a storage index on a model
+	IndexCollision ProblemKind = "index collision"
+	// Two settings share a name, getByName only ever finds the first. Only a
+	// warning when their keys tell them apart.
+	DuplicateName ProblemKind = "duplicate name"
+	// Two settings share a key, or a setting has none
+	DuplicateKey ProblemKind = "duplicate key"
+	MissingKey   ProblemKind = "missing key"
+	// A category outside of Categories
+	UnknownCategory ProblemKind = "unknown category"
+	// A value table uses the same ID twice
+	DuplicateValueID ProblemKind = "duplicate value id"
+	// A setting has no default value
+	MissingDefault ProblemKind = "missing default"
+	// A default value that is not in the value table
+	InvalidDefault ProblemKind = "invalid default"
+)
+
+// DefinitionProblem is an inconsistency found by ValidateSettings.
+type DefinitionProblem struct {
+	Kind     ProblemKind
+	Settings []string
+
+	// Model and Index of an index collision, ID of a duplicate value ID, Key
+	// of a duplicate key, Keys of the settings sharing an index or a name
+	Model types.Model
+	Index int
+	ID    int
+	Key   string
+	Keys  []string
+
+	// Why an index collision is known and left in the definitions
+	Known string
+}
+
+// Warning reports problems that do not make the table ambiguous, and known
+// collisions that wait for a capture.
+func (p DefinitionProblem) Warning() bool {
+	switch p.Kind {
+	case MissingDefault:
+		return true
+	case IndexCollision:
+		return p.Known != ""
+	case DuplicateName:
+		return distinctKeys(p.Keys)
+	}
+
+	return false
+}
+
+func (p DefinitionProblem) String() string {
+	names := strings.Join(p.Settings, ", ")
+
+	switch p.Kind {
+	case IndexCollision:
+		if p.Known != "" {
+			return fmt.Sprintf("%s: %s index %d is used by %s, known: %s", p.Kind, p.Model, p.Index, names, p.Known)
+		}
+		return fmt.Sprintf("%s: %s index %d is used by %s", p.Kind, p.Model, p.Index, names)
+	case DuplicateName:
+		return fmt.Sprintf("%s: %s with keys %s", p.Kind, names, strings.Join(p.Keys, ", "))
+	case DuplicateKey:
+		return fmt.Sprintf("%s: %s is used by %s", p.Kind, p.Key, names)
+	case DuplicateValueID:
+		return fmt.Sprintf("%s: %s uses id %d more than once", p.Kind, names, p.ID)
+	}
+
+	return fmt.Sprintf("%s: %s", p.Kind, names)
+}
+
+// knownCollisions are settings, by key, known to share a storage index. They
+// are still reported, as warnings, until a capture shows which one the index
+// holds.
+var knownCollisions = []struct {
+	keys   []string
+	reason string
+}{
+	{
+		keys:   []string{"display.all_threats", "display.scan_icon"},
+		reason: "both came with the original definitions, neither is confirmed on a device",
+	},
+	{
+		keys:   []string{"display.auto_dim_mode", "display.dark_mode"},
+		reason: "both came with the original definitions, their value tables differ so only one can be right",
+	},
+	{
+		keys:   []string{"audio.mute_memory_bands_auto", "gps.auto_mute_memory"},
+		reason: "a boolean and a band table under one name, no capture tells which the index holds",
+	},
+	{
+		// The other R8 band colors sit 8 above their R4 index, which points
+		// at 63, but no capture confirms it
+		keys:   []string{"display.background_color", "display.ka_color"},
+		reason: "R8 Ka band color is unconfirmed",
+	},
+}
+
+// knownCollision returns why a collision between keys is known, or "".
+func knownCollision(keys []string) string {
+	sorted := slices.Clone(keys)
+	slices.Sort(sorted)
+
+	for _, known := range knownCollisions {
+		if slices.Equal(sorted, known.keys) {
+			return known.reason
+		}
+	}
+
+	return ""
+}
+
+func distinctKeys(keys []string) bool {
+	seen := map[string]bool{}
+	for _, key := range keys {
+		if key == "" || seen[key] {
+			return false
+		}
+		seen[key] = true
+	}
+
+	return len(keys) > 0
+}
+
+// ValidateSettings checks a settings table for per-model index collisions,
+// duplicate names and keys, unknown categories, value tables with duplicate
+// IDs and missing or invalid defaults. Value tables of speed settings are
+// generated and not checked. Collisions in knownCollisions are reported as
+// warnings.
+func ValidateSettings(settings Settings) []DefinitionProblem {
+	var problems []DefinitionProblem
+
+	// Index collisions, per model
+	for _, model := range types.Models {
+		byIndex := map[int][]*Setting{}
+		for _, setting := range settings {
+			if index, ok := setting.StorageIndex[model]; ok {
+				byIndex[index] = append(byIndex[index], setting)
+			}
+		}
+
+		for _, index := range sortedKeys(byIndex) {
+			if shared := byIndex[index]; len(shared) > 1 {
+				problem := DefinitionProblem{Kind: IndexCollision, Model: model, Index: index}
+				for _, setting := range shared {
+					problem.Settings = append(problem.Settings, setting.Name)
+					problem.Keys = append(problem.Keys, setting.Key)
+				}
+				problem.Known = knownCollision(problem.Keys)
+				problems = append(problems, problem)
+			}
+		}
+	}
+
+	// Duplicate names, compared the way getByName compares them. lookup tries
+	// keys first, so settings with their own keys stay reachable.
+	byName := map[string][]*Setting{}
+	var order []string
+	for _, setting := range settings {
+		name := strings.ToLower(setting.Name)
+		if _, ok := byName[name]; !ok {
+			order = append(order, name)
+		}
+		byName[name] = append(byName[name], setting)
+	}
+
+	for _, name := range order {
+		if shared := byName[name]; len(shared) > 1 {
+			problem := DefinitionProblem{Kind: DuplicateName}
+			for _, setting := range shared {
+				problem.Settings = append(problem.Settings, setting.Name)
+				problem.Keys = append(problem.Keys, setting.Key)
+			}
+			problems = append(problems, problem)
+		}
+	}
+
+	byKey := map[string][]string{}
+	var keys []string
+	for _, setting := range settings {
+		if setting.Key == "" {
+			problems = append(problems, DefinitionProblem{Kind: MissingKey, Settings: []string{setting.Name}})
+			continue
+		}
+
+		if _, ok := byKey[setting.Key]; !ok {
+			keys = append(keys, setting.Key)
+		}
+		byKey[setting.Key] = append(byKey[setting.Key], setting.Name)
+	}
+
+	for _, key := range keys {
+		if names := byKey[key]; len(names) > 1 {
+			problems = append(problems, DefinitionProblem{Kind: DuplicateKey, Settings: names, Key: key})
+		}
+	}
+
+	for _, setting := range settings {
+		if !slices.Contains(Categories, setting.Category) {
+			problems = append(problems, DefinitionProblem{Kind: UnknownCategory, Settings: []string{setting.Name}})
+		}
+
+		if setting.DynamicValues == nil {
+			seen := map[int]bool{}
+			for _, value := range setting.Values {
+				if seen[value.ID] {
+					problems = append(problems, DefinitionProblem{Kind: DuplicateValueID, Settings: []string{setting.Name}, ID: value.ID})
+				}
+				seen[value.ID] = true
+			}
+		}
+
+		if setting.DefaultValue == nil {
+			problems = append(problems, DefinitionProblem{Kind: MissingDefault, Settings: []string{setting.Name}})
+			continue
+		}
+
+		if setting.DynamicValues == nil && !validDefault(setting) {
+			problems = append(problems, DefinitionProblem{Kind: InvalidDefault, Settings: []string{setting.Name}})
+		}
+	}
+
+	return problems
+}
+
+// ValidateDefinitions checks the settings definitions in use.
+func ValidateDefinitions() []DefinitionProblem {
+	return ValidateSettings(definitions())
+}
+
+func validDefault(setting *Setting) bool {
+	var id int
+
+	switch v := setting.DefaultValue.(type) {
+	case bool:
+		if v {
+			id = 1
+		}
+	case int:
+		id = v
+	default:
+		return false
+	}
+
+	return setting.ValidateValueInt(id) == nil
+}
+
+func sortedKeys(m map[int][]*Setting) []int {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+
+	return keys
+}
@@ -0,0 +1,84 @@
+package uniden
+
+import (
+	"testing"
+
+	"github.com/smoke7385/smk-uniden-bluetooth/types"
+)
+
+func TestValidateDefinitions(t *testing.T) {
+	for _, problem := range ValidateDefinitions() {
+		if !problem.Warning() {
+			t.Error(problem)
+		}
+	}
+}
+
+func TestValidateSettingsReportsCollisions(t *testing.T) {
+	settings := newSettingsSnapshot(types.AutoDetect)
+	settings[1].StorageIndex = settings[0].StorageIndex
+
+	var collisions int
+	for _, problem := range ValidateSettings(settings) {
+		if problem.Kind == IndexCollision {
+			collisions++
+		}
+	}
+
+	if collisions == 0 {
+		t.Error("no index collision reported for two settings at the same index")
+	}
+}
+
+func TestValidateDefinitionsReportsKnownCollisions(t *testing.T) {
+	var known int
+	for _, problem := range ValidateDefinitions() {
+		if problem.Kind == IndexCollision && problem.Known != "" {
+			known++
+		}
+	}
+
+	// Every model carries both known collisions
+	if want := 2 * len(types.Models); known < want {
+		t.Errorf("%d known collisions reported, want at least %d", known, want)
+	}
+}
+
+func TestValidateSettingsDuplicateNames(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		warning bool
+	}{
+		{"told apart by key", "test.other", true},
+		{"same key", "", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			settings := newSettingsSnapshot(types.AutoDetect)
+			settings[0].Name = "Test duplicate"
+			settings[1].Name = settings[0].Name
+			settings[1].Key = test.key
+			if test.key == "" {
+				settings[1].Key = settings[0].Key
+			}
+
+			var found bool
+			for _, problem := range ValidateSettings(settings) {
+				if problem.Kind != DuplicateName || problem.Settings[0] != settings[0].Name {
+					continue
+				}
+				found = true
+
+				if problem.Warning() != test.warning {
+					t.Errorf("%s: warning %v, want %v", problem, problem.Warning(), test.warning)
+				}
+			}
+
+			if !found {
+				t.Error("duplicate name not reported")
+			}
+		})
+	}
+}
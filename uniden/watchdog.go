@@ -0,0 +1,198 @@
+package uniden
+
+import (
+	"time"
+
+	"github.com/smoke7385/smk-uniden-bluetooth/types"
+	"github.com/smoke7385/smk-uniden-bluetooth/utils"
+)
+
+// WatchdogConfig controls the link health watchdog. It assumes the status
+// characteristic notifies continuously while the detector is on, so that a
+// stalled status stream separates a dead link from a quiet road. No capture
+// confirms that yet, which is why ReconnectOnLoss is off by default.
+type WatchdogConfig struct {
+	Enabled  bool
+	Interval time.Duration
+
+	// Status stream silence before the link counts as degraded or lost
+	DegradedAfter time.Duration
+	LostAfter     time.Duration
+
+	// RSSI below this marks the link as degraded. The Bluetooth transport
+	// only reports RSSI while a scan runs, so outside one this has no effect.
+	MinRSSI int16
+
+	// Tear the link down and go through the reconnect path once it is lost.
+	// Opt in once the device is known to notify status continuously,
+	// otherwise a quiet but healthy link gets dropped.
+	ReconnectOnLoss bool
+}
+
+var DefaultWatchdogConfig = WatchdogConfig{
+	Enabled:         true,
+	Interval:        1 * time.Second,
+	DegradedAfter:   5 * time.Second,
+	LostAfter:       15 * time.Second,
+	MinRSSI:         -90,
+	ReconnectOnLoss: false,
+}
+
+// LinkHealth is the watchdog's verdict on the link.
+type LinkHealth int
+
+const (
+	LinkHealthy LinkHealth = iota
+	LinkDegraded
+	LinkLost
+)
+
+func (h LinkHealth) String() string {
+	switch h {
+	case LinkHealthy:
+		return "Healthy"
+	case LinkDegraded:
+		return "Degraded"
+	case LinkLost:
+		return "Lost"
+	default:
+		return "Unknown"
+	}
+}
+
+// LinkStatus is a snapshot of the link numbers tracked by the watchdog.
+type LinkStatus struct {
+	State  ConnectionState
+	Health LinkHealth
+	MTU    uint16
+
+	RSSI          int16
+	RSSIAvailable bool
+	RSSISampledAt time.Time
+
+	// Time of the last notification on each characteristic
+	LastNotification map[types.CharType]time.Time
+}
+
+// SinceLast returns how long the given characteristic has been silent.
+func (l LinkStatus) SinceLast(uuid types.CharType) time.Duration {
+	last, ok := l.LastNotification[uuid]
+	if !ok {
+		return 0
+	}
+
+	return time.Since(last)
+}
+
+// LinkEvent is emitted whenever the link health changes.
+type LinkEvent struct {
+	Health LinkHealth
+	Status LinkStatus
+}
+
+func (m *Uniden) OnLinkEvent(callback func(e LinkEvent)) {
+	m.onLinkEvent = callback
+}
+
+func (m *Uniden) LinkStatus() LinkStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	status := m.link
+	status.State = m.state
+	status.MTU = m.mtu
+	status.LastNotification = map[types.CharType]time.Time{}
+	for uuid, t := range m.link.LastNotification {
+		status.LastNotification[uuid] = t
+	}
+
+	return status
+}
+
+func (m *Uniden) markNotification(uuid types.CharType) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.link.LastNotification[uuid] = time.Now()
+}
+
+func (m *Uniden) startWatchdog() {
+	m.stopWatchdog()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Give the status stream a full window from the moment we connected
+	m.link.Health = LinkHealthy
+	m.link.LastNotification = map[types.CharType]time.Time{
+		types.C.Status: time.Now(),
+	}
+
+	if !m.Watchdog.Enabled || m.closed {
+		return
+	}
+
+	m.watchdogDone = utils.SetIntervalGroup(m.checkLink, m.Watchdog.Interval, &m.wg)
+}
+
+func (m *Uniden) stopWatchdog() {
+	m.mu.Lock()
+	done := m.watchdogDone
+	m.watchdogDone = nil
+	m.mu.Unlock()
+
+	if done != nil {
+		close(done)
+	}
+}
+
+func (m *Uniden) checkLink() {
+	rssi, rssiErr := m.transport.RSSI()
+
+	m.mu.Lock()
+	if m.state != Ready {
+		m.mu.Unlock()
+		return
+	}
+
+	// RSSISampledAt keeps the time of the last real sample
+	if rssiErr == nil {
+		m.link.RSSI = rssi
+		m.link.RSSIAvailable = true
+		m.link.RSSISampledAt = time.Now()
+	} else {
+		m.link.RSSIAvailable = false
+	}
+
+	silence := time.Since(m.link.LastNotification[types.C.Status])
+	config := m.Watchdog
+
+	health := LinkHealthy
+	switch {
+	case silence >= config.LostAfter:
+		health = LinkLost
+	case silence >= config.DegradedAfter:
+		health = LinkDegraded
+	case m.link.RSSIAvailable && m.link.RSSI < config.MinRSSI:
+		health = LinkDegraded
+	}
+
+	changed := health != m.link.Health
+	m.link.Health = health
+	m.mu.Unlock()
+
+	if !changed {
+		return
+	}
+
+	m.println("Link health:", health.String())
+
+	if m.onLinkEvent != nil {
+		(m.onLinkEvent)(LinkEvent{Health: health, Status: m.LinkStatus()})
+	}
+
+	if health == LinkLost && config.ReconnectOnLoss {
+		m.transport.Disconnect()
+		m.handleLinkLost()
+	}
+}
@@ -5,6 +5,7 @@ import (
 	JSON "encoding/json"
 	"fmt"
 	"strconv"
+	"sync"
 	"time"
 )
 
@@ -63,16 +64,6 @@ func Map[T, U any](ts []T, f func(T) U) []U {
 	return us
 }
 
-func ParseInt(str string) int {
-	p, err := strconv.Atoi(str)
-
-	if err != nil {
-		panic("failed to parse int: " + err.Error())
-	}
-
-	return p
-}
-
 func LogStruct(s interface{}) {
 	fmt.Printf("%+v\n", s)
 }
@@ -95,6 +86,30 @@ func SetInterval(callback func(), interval time.Duration) chan bool {
 	return done
 }
 
+// SetIntervalGroup is SetInterval with the goroutine tracked by wg, so callers
+// can wait for it to exit after closing the returned channel.
+func SetIntervalGroup(callback func(), interval time.Duration, wg *sync.WaitGroup) chan bool {
+	ticker := time.NewTicker(interval)
+	done := make(chan bool)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				callback()
+			}
+		}
+	}()
+
+	return done
+}
+
 // IntRange represents a range of integers.
 type IntRange struct {
 	Start int